@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,7 +12,10 @@ import (
 
 	"github.com/spf13/viper"
 	"github.com/stakater/Forecastle/v1/internal/web"
+	"github.com/stakater/Forecastle/v1/internal/webhook"
+	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/kube/leader"
 	"github.com/stakater/Forecastle/v1/pkg/log"
 )
 
@@ -32,7 +36,16 @@ func init() {
 func main() {
 	// Parse command line flags
 	port := flag.Int("port", 3000, "Server port")
+	metricsPort := flag.Int("metrics-port", 0, "Metrics server port, 0 to disable metrics")
 	cacheInterval := flag.Duration("cache-interval", 20*time.Second, "Background cache refresh interval")
+	leaderElect := flag.Bool("leader-elect", false, "Elect a leader among replicas to write ForecastleApp statuses")
+	leaderElectionID := flag.String("leader-election-id", "forecastle", "Name of the Lease used for leader election")
+	leaderElectionNamespace := flag.String("leader-election-namespace", os.Getenv("KUBERNETES_NAMESPACE"), "Namespace of the Lease used for leader election")
+	webhookMode := flag.Bool("webhook", false, "Run the validating admission webhook server instead of the dashboard")
+	webhookPort := flag.Int("webhook-port", 9443, "Admission webhook server port")
+	tlsCertFile := flag.String("tls-cert-file", "/etc/forecastle/webhook/tls.crt", "TLS certificate of the admission webhook server")
+	tlsKeyFile := flag.String("tls-key-file", "/etc/forecastle/webhook/tls.key", "TLS key of the admission webhook server")
+	webhookWarnOnly := flag.Bool("webhook-warn-only", false, "Admit invalid objects with warnings instead of rejecting them")
 	flag.Parse()
 
 	// Create context that cancels on interrupt
@@ -48,16 +61,60 @@ func main() {
 		cancel()
 	}()
 
+	appConfig, err := config.GetConfig()
+	if err != nil {
+		logger.Error("Invalid config: ", err)
+		os.Exit(1)
+	}
+
+	if *webhookMode {
+		logger.Info("Forecastle admission webhook starting...")
+		err := webhook.RunServer(ctx, webhook.ServerConfig{
+			Port:     *webhookPort,
+			CertFile: *tlsCertFile,
+			KeyFile:  *tlsKeyFile,
+			Config:   *appConfig,
+			WarnOnly: *webhookWarnOnly,
+		})
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Webhook server error: ", err)
+			os.Exit(1)
+		}
+		logger.Info("Forecastle admission webhook stopped")
+		return
+	}
+
 	// Initialize Kubernetes clients
 	clients := kube.GetClients()
 
 	// Configure server
 	cfg := web.ServerConfig{
 		Port:          *port,
+		MetricsPort:   *metricsPort,
 		CacheInterval: *cacheInterval,
 		BasePath:      viper.GetString("basePath"),
 	}
 
+	if appConfig.Auth.OIDC.Enabled {
+		cfg.OIDC = &appConfig.Auth.OIDC
+		_, cfg.TrustedProxies, _ = appConfig.ClientNetworks.Parse()
+	}
+	cfg.APIAuth = appConfig.Auth.API
+	cfg.UIAuth = appConfig.Auth.UI
+
+	if *leaderElect {
+		identity, err := os.Hostname()
+		if err != nil {
+			logger.Error("Unable to determine leader election identity: ", err)
+			os.Exit(1)
+		}
+		cfg.LeaderElection = &leader.Config{
+			LeaseName:      *leaderElectionID,
+			LeaseNamespace: *leaderElectionNamespace,
+			Identity:       identity,
+		}
+	}
+
 	// Start server
 	logger.Info("Forecastle starting...")
 	if err := web.RunServer(ctx, &clients, cfg); err != nil {
@@ -21,7 +21,7 @@ const AppGridView = ({ apps }) => {
             {apps[groupName].map((app, index) => (
               <Grid
                 item
-                key={`${app.name}-${index}`}
+                key={app.id || `${app.name}-${index}`}
                 xs={12}
                 sm={6}
                 md={4}
@@ -28,7 +28,7 @@ const AppListView = ({ apps }) => {
             }}
           >
             {apps[groupName].map((app, index) => (
-              <AppListItem key={`${app.name}-${index}`} app={app} />
+              <AppListItem key={app.id || `${app.name}-${index}`} app={app} />
             ))}
           </Paper>
         </AppGroup>
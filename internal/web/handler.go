@@ -3,18 +3,26 @@ package web
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle/crdapps"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle/customapps"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/groups"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle/httprouteapps"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle/ingressapps"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/merge"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/rewrite"
 	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/kube/leader"
+	"github.com/stakater/Forecastle/v1/pkg/kube/rbac"
 	"github.com/stakater/Forecastle/v1/pkg/kube/util"
 	"github.com/stakater/Forecastle/v1/pkg/log"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,12 +37,22 @@ type AppsResponse struct {
 	ExpiresAt time.Time        `json:"expiresAt"`
 }
 
+// AppDetailResponse is the response structure for the /api/apps/{id} endpoint
+type AppDetailResponse struct {
+	forecastle.App
+	// Annotations holds the raw forecastle annotations the app was built from
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 // Handler handles HTTP requests with background caching
 type Handler struct {
 	clients       *kube.Clients
 	configFunc    func() (*config.Config, error)
 	cacheInterval time.Duration
 
+	// elector decides whether this replica writes ForecastleApp statuses, nil when leader election is disabled
+	elector *leader.Elector
+
 	// Cached apps data
 	appsCache     []forecastle.App
 	appsCacheMu   sync.RWMutex
@@ -43,6 +61,11 @@ type Handler struct {
 	// Cached config
 	configCache   *config.Config
 	configCacheMu sync.RWMutex
+
+	// authorizer reviews the access of users to the namespaces of apps, nil when RBAC visibility is disabled.
+	// It is guarded by configCacheMu and recreated when the RBAC config changes
+	authorizer *rbac.Authorizer
+	rbacConfig config.RBAC
 }
 
 // NewHandler creates a new Handler instance
@@ -84,6 +107,13 @@ func (h *Handler) refreshCache(ctx context.Context) {
 	} else {
 		h.configCacheMu.Lock()
 		h.configCache = cfg
+		if cfg.Auth.RBAC != h.rbacConfig {
+			h.rbacConfig = cfg.Auth.RBAC
+			h.authorizer = nil
+			if cfg.Auth.RBAC.Enabled {
+				h.authorizer = rbac.NewAuthorizer(h.clients.KubernetesClient, cfg.Auth.RBAC)
+			}
+		}
 		h.configCacheMu.Unlock()
 	}
 
@@ -124,10 +154,25 @@ func (h *Handler) discoverApps(cfg *config.Config) ([]forecastle.App, error) {
 	}
 	logger.Info("Looking for forecastle apps in namespaces: " + namespacesString)
 
+	// Namespace annotations and labels provide defaults for the apps discovered in them
+	namespaceObjects, err := util.GetNamespaces(h.clients.KubernetesClient, namespaces)
+	if err != nil {
+		logger.Warn("Error fetching namespace defaults: ", err)
+	}
+	scheme := cfg.GetAnnotationScheme()
+	for _, namespace := range namespaceObjects {
+		namespace.Annotations = scheme.Normalize(namespace.Annotations)
+		namespace.Labels = scheme.Normalize(namespace.Labels)
+	}
+
+	// Access restrictions are detected from ingress controller annotations and Traefik middlewares
+	detector := access.NewDetector(*cfg, *h.clients)
+
 	var allApps []forecastle.App
 
 	// Discover from Ingress resources
-	ingressAppsList := ingressapps.NewList(h.clients.KubernetesClient, *cfg)
+	ingressAppsList := ingressapps.NewList(h.clients.KubernetesClient, *cfg).WithNamespaces(namespaceObjects).
+		WithAccessDetector(detector)
 	ingressApps, err := ingressAppsList.Populate(namespaces...).Get()
 	if err != nil {
 		logger.Error("Error discovering ingress apps: ", err)
@@ -137,7 +182,7 @@ func (h *Handler) discoverApps(cfg *config.Config) ([]forecastle.App, error) {
 
 	// Discover from HTTPRoute resources (Gateway API)
 	if h.clients.GatewayClient != nil {
-		httpRouteAppsList := httprouteapps.NewList(h.clients.GatewayClient, *cfg)
+		httpRouteAppsList := httprouteapps.NewList(h.clients.GatewayClient, *cfg).WithNamespaces(namespaceObjects).WithAccessDetector(detector)
 		httpRouteApps, err := httpRouteAppsList.Populate(namespaces...).Get()
 		if err != nil {
 			logger.Error("Error discovering HTTPRoute apps: ", err)
@@ -157,7 +202,8 @@ func (h *Handler) discoverApps(cfg *config.Config) ([]forecastle.App, error) {
 
 	// Discover from CRD if enabled
 	if cfg.CRDEnabled {
-		crdAppsList := crdapps.NewList(*h.clients, *cfg)
+		crdAppsList := crdapps.NewList(*h.clients, *cfg).WithNamespaces(namespaceObjects).
+			WithStatusUpdates(cfg.CRDStatusEnabled && h.elector.IsLeader()).WithAccessDetector(detector)
 		crdApps, err := crdAppsList.Populate(namespaces...).Get()
 		if err != nil {
 			logger.Error("Error discovering CRD apps: ", err)
@@ -166,20 +212,68 @@ func (h *Handler) discoverApps(cfg *config.Config) ([]forecastle.App, error) {
 		}
 	}
 
+	// Rewrite URLs before merging so apps are merged on the URLs users reach them at
+	rewrite.Apps(allApps, *cfg)
+
+	// Nest groups before merging so merged apps keep the group of their highest precedence source
+	groups.Apply(allApps, *cfg)
+
+	// Assign stable IDs before merging so merged apps keep the ID of their highest precedence source
+	for i := range allApps {
+		allApps[i].ID = forecastle.NewID(cfg.ClusterName, allApps[i].DiscoverySource, allApps[i].Origin)
+	}
+
+	// Merge apps discovered through multiple sources
+	if cfg.Deduplication.Enabled {
+		allApps = merge.Apps(allApps, *cfg)
+	}
+
 	if allApps == nil {
 		allApps = []forecastle.App{}
 	}
 
+	forecastle.SortApps(allApps, func(slug string) int {
+		group, _ := cfg.GetGroup(slug)
+		return group.Order
+	})
+
 	return allApps, nil
 }
 
-// AppsHandler handles GET /api/apps
+// getCachedConfig returns the cached config, nil if the cache isn't populated yet
+func (h *Handler) getCachedConfig() *config.Config {
+	h.configCacheMu.RLock()
+	defer h.configCacheMu.RUnlock()
+	return h.configCache
+}
+
+// getAuthorizer returns the authorizer of RBAC visibility, nil if it is disabled
+func (h *Handler) getAuthorizer() *rbac.Authorizer {
+	h.configCacheMu.RLock()
+	defer h.configCacheMu.RUnlock()
+	return h.authorizer
+}
+
+// visibleApps returns apps as seen by the client of r, filtered by its network and its user
+func (h *Handler) visibleApps(r *http.Request, apps []forecastle.App, cfg *config.Config) []forecastle.App {
+	return appsForUser(r, appsForClient(r, apps, cfg), cfg, h.getAuthorizer())
+}
+
+// AppsHandler handles GET /api/apps. Apps are sorted by group, weight and name. Repeated ?tag= parameters
+// only return apps carrying all of the given tags. Network restricted apps are hidden or marked unreachable
+// for clients outside the configured internal networks, and apps are hidden from users outside their allowed
+// groups or, with RBAC visibility, without access to their namespace
 func (h *Handler) AppsHandler(w http.ResponseWriter, r *http.Request) {
 	h.appsCacheMu.RLock()
 	apps := h.appsCache
 	cacheTime := h.appsCacheTime
 	h.appsCacheMu.RUnlock()
 
+	apps = h.visibleApps(r, apps, h.getCachedConfig())
+	if tags := r.URL.Query()["tag"]; len(tags) > 0 {
+		apps = filterByTags(apps, tags)
+	}
+
 	// Ensure apps is never nil
 	if apps == nil {
 		apps = []forecastle.App{}
@@ -198,7 +292,101 @@ func (h *Handler) AppsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ConfigHandler handles GET /api/config
+// GroupsHandler handles GET /api/groups, returning apps nested in their group hierarchy. Repeated ?tag=
+// parameters only return apps carrying all of the given tags
+func (h *Handler) GroupsHandler(w http.ResponseWriter, r *http.Request) {
+	h.appsCacheMu.RLock()
+	apps := h.appsCache
+	h.appsCacheMu.RUnlock()
+
+	cfg := h.getCachedConfig()
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	apps = h.visibleApps(r, apps, cfg)
+
+	if tags := r.URL.Query()["tag"]; len(tags) > 0 {
+		apps = filterByTags(apps, tags)
+	}
+
+	tree := groups.Tree(apps, *cfg)
+	if tree == nil {
+		tree = []*groups.Node{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tree); err != nil {
+		logger.Error("Error encoding groups response: ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// filterByTags returns the apps carrying all of tags
+func filterByTags(apps []forecastle.App, tags []string) []forecastle.App {
+	var filtered []forecastle.App
+	for _, app := range apps {
+		hasTags := true
+		for _, tag := range tags {
+			if !app.HasTag(tag) {
+				hasTags = false
+				break
+			}
+		}
+		if hasTags {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered
+}
+
+// AppHandler handles GET /api/apps/{id}
+func (h *Handler) AppHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	h.appsCacheMu.RLock()
+	apps := h.appsCache
+	h.appsCacheMu.RUnlock()
+
+	apps = h.visibleApps(r, apps, h.getCachedConfig())
+
+	for _, app := range apps {
+		if app.ID != id {
+			continue
+		}
+
+		response := AppDetailResponse{
+			App:         app,
+			Annotations: detailAnnotations(app.Annotations),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error encoding app response: ", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Error(w, "app not found", http.StatusNotFound)
+}
+
+// hiddenAnnotations are left out of app details, so the groups allowed to see apps aren't disclosed
+var hiddenAnnotations = []string{annotations.ForecastleAllowedGroupsAnnotation}
+
+// detailAnnotations returns annots without the hidden annotations, nil if none are left
+func detailAnnotations(annots map[string]string) map[string]string {
+	visible := maps.Clone(annots)
+	for _, key := range hiddenAnnotations {
+		delete(visible, key)
+	}
+	if len(visible) == 0 {
+		return nil
+	}
+	return visible
+}
+
+// ConfigHandler handles GET /api/config. Custom apps aren't returned, they are served by /api/apps, which
+// hides them from clients and users that may not see them
 func (h *Handler) ConfigHandler(w http.ResponseWriter, r *http.Request) {
 	h.configCacheMu.RLock()
 	cfg := h.configCache
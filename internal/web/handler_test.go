@@ -5,17 +5,21 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
-	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
 	forecastlefake "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/fake"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/groups"
 	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/kube/leader"
 	"github.com/stakater/Forecastle/v1/pkg/testutil"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
@@ -82,6 +86,69 @@ func TestHandler_DiscoverApps_IngressOnly(t *testing.T) {
 	}
 }
 
+func TestHandler_DiscoverApps_AnnotationDialects(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+
+	_, _ = kubeClient.CoreV1().Namespaces().Create(
+		context.TODO(),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{"example.com/icon": "https://example.com/default.png"},
+		}},
+		metav1.CreateOptions{},
+	)
+
+	hajimari := testutil.CreateIngressWithHost("hajimari-app", "hajimari.example.com")
+	hajimari.Namespace = "default"
+	hajimari.Annotations = map[string]string{
+		"hajimari.io/enable":  "true",
+		"hajimari.io/appName": "Hajimari App",
+		"hajimari.io/group":   "Migrated",
+	}
+	homepage := testutil.CreateIngressWithHost("homepage-app", "homepage.example.com")
+	homepage.Namespace = "default"
+	homepage.Annotations = map[string]string{
+		"gethomepage.dev/enabled": "true",
+		"gethomepage.dev/name":    "Homepage App",
+		"example.com/group":       "Forked",
+	}
+	// Annotations with the default prefix are ignored when another prefix is configured
+	stock := testutil.CreateIngressWithHost("stock-app", "stock.example.com")
+	stock.Namespace = "default"
+	stock.Annotations = map[string]string{annotations.ForecastleExposeAnnotation: "true"}
+	for _, ingress := range []*networkingv1.Ingress{hajimari, homepage, stock} {
+		_, _ = kubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingress, metav1.CreateOptions{})
+	}
+
+	clients := &kube.Clients{
+		KubernetesClient:     kubeClient,
+		ForecastleAppsClient: forecastlefake.NewSimpleClientset(),
+	}
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		Annotations:       config.Annotations{Prefix: "example.com", Dialects: []string{"hajimari", "homepage"}},
+	}
+
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	apps, err := handler.discoverApps(cfg)
+	if err != nil {
+		t.Fatalf("discoverApps() error = %v", err)
+	}
+
+	want := []struct{ name, group string }{
+		{"Homepage App", "forked"},
+		{"Hajimari App", "migrated"},
+	}
+	if len(apps) != len(want) {
+		t.Fatalf("Expected %d apps, got %d", len(want), len(apps))
+	}
+	for i, w := range want {
+		if apps[i].Name != w.name || apps[i].Group != w.group || apps[i].Icon != "https://example.com/default.png" {
+			t.Errorf("Expected app %q in group %q with the namespace icon, got %q in %q with icon %q", w.name, w.group, apps[i].Name, apps[i].Group, apps[i].Icon)
+		}
+	}
+}
+
 func TestHandler_DiscoverApps_HTTPRouteOnly(t *testing.T) {
 	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
 	forecastleClient := forecastlefake.NewSimpleClientset()
@@ -152,19 +219,19 @@ func TestHandler_DiscoverApps_CRDOnly(t *testing.T) {
 	)
 
 	// Create a ForecastleApp CRD
-	forecastleApp := &v1alpha1.ForecastleApp{
+	forecastleApp := &v1beta1.ForecastleApp{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "crd-app",
 			Namespace: "default",
 		},
-		Spec: v1alpha1.ForecastleAppSpec{
+		Spec: v1beta1.ForecastleAppSpec{
 			Name:  "CRD Application",
 			URL:   "https://crd.example.com",
 			Icon:  "https://example.com/crd-icon.png",
 			Group: "CRD Apps",
 		},
 	}
-	_, _ = forecastleClient.ForecastleV1alpha1().ForecastleApps("default").Create(forecastleApp)
+	_, _ = forecastleClient.ForecastleV1beta1().ForecastleApps("default").Create(forecastleApp)
 
 	clients := &kube.Clients{
 		KubernetesClient:     kubeClient,
@@ -284,18 +351,18 @@ func TestHandler_DiscoverApps_AllSources(t *testing.T) {
 	_, _ = gatewayClient.GatewayV1().HTTPRoutes("default").Create(context.TODO(), httpRoute, metav1.CreateOptions{})
 
 	// Create ForecastleApp CRD
-	forecastleApp := &v1alpha1.ForecastleApp{
+	forecastleApp := &v1beta1.ForecastleApp{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "crd-app",
 			Namespace: "default",
 		},
-		Spec: v1alpha1.ForecastleAppSpec{
+		Spec: v1beta1.ForecastleAppSpec{
 			Name:  "CRD App",
 			URL:   "https://crd.example.com",
 			Group: "CRD",
 		},
 	}
-	_, _ = forecastleClient.ForecastleV1alpha1().ForecastleApps("default").Create(forecastleApp)
+	_, _ = forecastleClient.ForecastleV1beta1().ForecastleApps("default").Create(forecastleApp)
 
 	clients := &kube.Clients{
 		KubernetesClient:     kubeClient,
@@ -464,6 +531,146 @@ func TestHandler_DiscoverApps_InstanceFiltering(t *testing.T) {
 	}
 }
 
+func TestHandler_DiscoverApps_NamespaceDefaults(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	forecastleClient := forecastlefake.NewSimpleClientset()
+
+	// Create namespace carrying forecastle defaults
+	_, _ = kubeClient.CoreV1().Namespaces().Create(
+		context.TODO(),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "team-payments",
+			Annotations: map[string]string{
+				annotations.OpenShiftDisplayNameAnnotation: "Payments",
+				annotations.ForecastlePropertiesAnnotation: "Owner:payments",
+			},
+			Labels: map[string]string{
+				annotations.ForecastleInstanceAnnotation: "forecastle-a",
+			},
+		}},
+		metav1.CreateOptions{},
+	)
+
+	// Create an ingress relying on the namespace defaults
+	ingress := testutil.AddAnnotationToIngress(
+		testutil.CreateIngressWithHost("checkout", "checkout.example.com"),
+		annotations.ForecastleExposeAnnotation, "true")
+	ingress.Namespace = "team-payments"
+	_, _ = kubeClient.NetworkingV1().Ingresses("team-payments").Create(context.TODO(), ingress, metav1.CreateOptions{})
+
+	// Create a ForecastleApp overriding the namespace group
+	forecastleApp := &v1beta1.ForecastleApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ledger",
+			Namespace: "team-payments",
+		},
+		Spec: v1beta1.ForecastleAppSpec{
+			Name:  "Ledger",
+			URL:   "https://ledger.example.com",
+			Group: "Finance",
+		},
+	}
+	_, _ = forecastleClient.ForecastleV1beta1().ForecastleApps("team-payments").Create(forecastleApp)
+
+	clients := &kube.Clients{
+		KubernetesClient:     kubeClient,
+		ForecastleAppsClient: forecastleClient,
+	}
+
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		InstanceName:      "forecastle-a",
+		CRDEnabled:        true,
+	}
+
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	apps, err := handler.discoverApps(cfg)
+
+	if err != nil {
+		t.Fatalf("discoverApps() error = %v", err)
+	}
+
+	if len(apps) != 2 {
+		t.Fatalf("Expected 2 apps (instance inherited from namespace), got %d", len(apps))
+	}
+
+	// Apps are sorted by group, so the ForecastleApp in 'finance' comes first
+	if apps[0].Group != "finance" {
+		t.Errorf("Expected ForecastleApp group 'finance', got '%s'", apps[0].Group)
+	}
+	if apps[0].Properties["Owner"] != "payments" {
+		t.Errorf("Expected ForecastleApp property Owner 'payments', got '%s'", apps[0].Properties["Owner"])
+	}
+	if apps[1].Group != "payments" {
+		t.Errorf("Expected ingress group 'payments' from namespace display name, got '%s'", apps[1].Group)
+	}
+	if apps[1].Properties["Owner"] != "payments" {
+		t.Errorf("Expected ingress property Owner 'payments', got '%s'", apps[1].Properties["Owner"])
+	}
+}
+
+func TestHandler_DiscoverApps_Deduplication(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	forecastleClient := forecastlefake.NewSimpleClientset()
+
+	// Create namespace
+	_, _ = kubeClient.CoreV1().Namespaces().Create(
+		context.TODO(),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		metav1.CreateOptions{},
+	)
+
+	// Create an exposed ingress that is also referenced by a ForecastleApp
+	ingress := testutil.AddAnnotationToIngress(
+		testutil.CreateIngressWithHost("grafana", "grafana.example.com"),
+		annotations.ForecastleExposeAnnotation, "true")
+	ingress.Namespace = "default"
+	_, _ = kubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingress, metav1.CreateOptions{})
+
+	forecastleApp := testutil.CreateForecastleAppWithURLFromIngress("Grafana", "Monitoring", "https://example.com/grafana.png", "grafana")
+	forecastleApp.Namespace = "default"
+	_, _ = forecastleClient.ForecastleV1beta1().ForecastleApps("default").Create(forecastleApp)
+
+	clients := &kube.Clients{
+		KubernetesClient:     kubeClient,
+		ForecastleAppsClient: forecastleClient,
+	}
+
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		CRDEnabled:        true,
+		Deduplication:     config.Deduplication{Enabled: true},
+		CustomApps: []config.CustomApp{
+			{
+				Name: "Grafana (config)",
+				URL:  "http://grafana.example.com/",
+			},
+		},
+	}
+
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	apps, err := handler.discoverApps(cfg)
+
+	if err != nil {
+		t.Fatalf("discoverApps() error = %v", err)
+	}
+
+	if len(apps) != 1 {
+		t.Fatalf("Expected 1 merged app, got %d", len(apps))
+	}
+
+	app := apps[0]
+	if app.Name != "Grafana (config)" {
+		t.Errorf("Expected name from highest precedence source 'Grafana (config)', got '%s'", app.Name)
+	}
+	if app.Icon != "https://example.com/grafana.png" {
+		t.Errorf("Expected icon from ForecastleApp, got '%s'", app.Icon)
+	}
+	if len(app.DiscoverySources) != 3 {
+		t.Errorf("Expected 3 discovery sources, got %v", app.DiscoverySources)
+	}
+}
+
 func TestHandler_DiscoverApps_WithoutGatewayClient(t *testing.T) {
 	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
 	forecastleClient := forecastlefake.NewSimpleClientset()
@@ -567,6 +774,238 @@ func TestHandler_AppsHandler_ReturnsJSON(t *testing.T) {
 	}
 }
 
+func TestHandler_AppsHandler_SortAndTagFilter(t *testing.T) {
+	clients := &kube.Clients{
+		KubernetesClient:     fake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+		ForecastleAppsClient: forecastlefake.NewSimpleClientset(),
+	}
+
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		CustomApps: []config.CustomApp{
+			{Name: "zeta", Group: "ops", URL: "https://zeta.example.com", Tags: []string{"monitoring"}},
+			{Name: "Alpha", Group: "ops", URL: "https://alpha.example.com", Weight: 10},
+			{Name: "beta", Group: "ops", URL: "https://beta.example.com", Tags: []string{"Monitoring", "docs"}},
+			{Name: "grafana", Group: "dev", URL: "https://grafana.example.com", Weight: 5, Tags: []string{"monitoring"}},
+		},
+	}
+
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	handler.refreshCache(context.Background())
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "SortedByGroupWeightAndName",
+			query: "",
+			want:  []string{"grafana", "beta", "zeta", "Alpha"},
+		},
+		{
+			name:  "FilteredByTag",
+			query: "?tag=monitoring",
+			want:  []string{"grafana", "beta", "zeta"},
+		},
+		{
+			name:  "FilteredByAllTags",
+			query: "?tag=monitoring&tag=docs",
+			want:  []string{"beta"},
+		},
+		{
+			name:  "FilteredByUnknownTag",
+			query: "?tag=unknown",
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.AppsHandler(rec, httptest.NewRequest(http.MethodGet, "/api/apps"+tt.query, nil))
+
+			var apps []forecastle.App
+			if err := json.NewDecoder(rec.Body).Decode(&apps); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			names := []string{}
+			for _, app := range apps {
+				names = append(names, app.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Expected apps %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestHandler_DiscoverApps_GroupConfig(t *testing.T) {
+	clients := &kube.Clients{
+		KubernetesClient:     fake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+		ForecastleAppsClient: forecastlefake.NewSimpleClientset(),
+	}
+
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		CustomApps: []config.CustomApp{
+			{Name: "argocd", Group: "Dev", URL: "https://argocd.example.com"},
+			{Name: "grafana", Group: "Observability", URL: "https://grafana.example.com"},
+			{Name: "vault", Group: "Platform", URL: "https://vault.example.com"},
+		},
+		Groups: map[string]config.Group{
+			"observability": {DisplayName: "Observability & Alerting", Order: -1},
+			"Platform":      {Order: 5, Collapsed: true},
+		},
+	}
+
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	apps, err := handler.discoverApps(cfg)
+	if err != nil {
+		t.Fatalf("discoverApps() error = %v", err)
+	}
+
+	want := []struct{ group, groupDisplayName string }{
+		{"observability", "Observability & Alerting"},
+		{"dev", "Dev"},
+		{"platform", "Platform"},
+	}
+	if len(apps) != len(want) {
+		t.Fatalf("Expected %d apps, got %d", len(want), len(apps))
+	}
+	for i, w := range want {
+		if apps[i].Group != w.group || apps[i].GroupDisplayName != w.groupDisplayName {
+			t.Errorf("Expected app %d in group %q (%q), got %q (%q)", i, w.group, w.groupDisplayName, apps[i].Group, apps[i].GroupDisplayName)
+		}
+	}
+}
+
+func TestHandler_GroupsHandler(t *testing.T) {
+	clients := &kube.Clients{
+		KubernetesClient:     fake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+		ForecastleAppsClient: forecastlefake.NewSimpleClientset(),
+	}
+
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		CustomApps: []config.CustomApp{
+			{Name: "grafana", Group: "Platform/Observability", URL: "https://grafana.example.com", Tags: []string{"monitoring"}},
+			{Name: "vault", Group: "Platform", URL: "https://vault.example.com"},
+			{Name: "argocd", Group: "Dev", URL: "https://argocd.example.com"},
+		},
+		Groups: map[string]config.Group{
+			"platform": {Order: -1},
+		},
+	}
+
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	handler.refreshCache(context.Background())
+
+	rec := httptest.NewRecorder()
+	handler.GroupsHandler(rec, httptest.NewRequest(http.MethodGet, "/api/groups", nil))
+
+	var tree []groups.Node
+	if err := json.NewDecoder(rec.Body).Decode(&tree); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(tree) != 2 || tree[0].Slug != "platform" || tree[1].Slug != "dev" {
+		t.Fatalf("Expected groups [platform dev], got %+v", tree)
+	}
+	platform := tree[0]
+	if len(platform.Apps) != 1 || platform.Apps[0].Name != "vault" {
+		t.Errorf("Expected vault in platform, got %+v", platform.Apps)
+	}
+	if len(platform.Children) != 1 || platform.Children[0].Slug != "platform/observability" || platform.Children[0].Name != "Observability" {
+		t.Fatalf("Expected platform/observability nested in platform, got %+v", platform.Children)
+	}
+	if apps := platform.Children[0].Apps; len(apps) != 1 || apps[0].ParentGroup != "platform" {
+		t.Errorf("Expected grafana with parent group platform, got %+v", apps)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.GroupsHandler(rec, httptest.NewRequest(http.MethodGet, "/api/groups?tag=monitoring", nil))
+	tree = nil
+	if err := json.NewDecoder(rec.Body).Decode(&tree); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(tree) != 1 || len(tree[0].Apps) != 0 || len(tree[0].Children) != 1 {
+		t.Errorf("Expected only the observability group to be returned, got %+v", tree)
+	}
+}
+
+func TestHandler_AppHandler(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	forecastleClient := forecastlefake.NewSimpleClientset()
+
+	// Create namespace
+	_, _ = kubeClient.CoreV1().Namespaces().Create(
+		context.TODO(),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		metav1.CreateOptions{},
+	)
+
+	// Create an ingress
+	ingress := testutil.AddAnnotationToIngress(
+		testutil.AddAnnotationToIngress(
+			testutil.CreateIngressWithHost("test-app", "test.example.com"),
+			annotations.ForecastleExposeAnnotation, "true"),
+		annotations.IngressClassAnnotation, "nginx")
+	ingress.Namespace = "default"
+	_, _ = kubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingress, metav1.CreateOptions{})
+
+	clients := &kube.Clients{
+		KubernetesClient:     kubeClient,
+		ForecastleAppsClient: forecastleClient,
+	}
+
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		ClusterName:       "prod",
+	}
+
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	handler.refreshCache(context.Background())
+
+	id := forecastle.NewID("prod", forecastle.Ingress, &forecastle.Origin{Namespace: "default", Name: "test-app"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/apps/"+id, nil)
+	req.SetPathValue("id", id)
+	rec := httptest.NewRecorder()
+
+	handler.AppHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var app map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&app); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if app["id"] != id {
+		t.Errorf("Expected id '%s', got '%v'", id, app["id"])
+	}
+	origin, _ := app["origin"].(map[string]interface{})
+	if origin["kind"] != "Ingress" || origin["namespace"] != "default" || origin["name"] != "test-app" {
+		t.Errorf("Expected origin Ingress default/test-app, got '%v'", origin)
+	}
+	annots, _ := app["annotations"].(map[string]interface{})
+	if len(annots) != 1 || annots[annotations.ForecastleExposeAnnotation] != "true" {
+		t.Errorf("Expected only the forecastle annotations, got '%v'", annots)
+	}
+
+	// Unknown ids are not found
+	req = httptest.NewRequest(http.MethodGet, "/api/apps/unknown", nil)
+	req.SetPathValue("id", "unknown")
+	rec = httptest.NewRecorder()
+
+	handler.AppHandler(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+}
+
 func TestHandler_HealthzHandler(t *testing.T) {
 	handler := NewHandler(nil, nil, time.Minute)
 
@@ -628,3 +1067,64 @@ func TestHandler_ReadyzHandler_Ready(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", rec.Code)
 	}
 }
+
+func TestHandler_DiscoverApps_CRDStatusOnlyWrittenByLeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		elector     *leader.Elector
+		wantWritten bool
+	}{
+		{
+			name:        "without leader election",
+			elector:     nil,
+			wantWritten: true,
+		},
+		{
+			name:        "not the leader",
+			elector:     &leader.Elector{},
+			wantWritten: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+			forecastleClient := forecastlefake.NewSimpleClientset()
+
+			_, _ = kubeClient.CoreV1().Namespaces().Create(
+				context.TODO(),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+				metav1.CreateOptions{},
+			)
+
+			forecastleApp := testutil.CreateForecastleApp("crd-app", "https://crd.example.com", "default", "https://example.com/crd-icon.png")
+			forecastleApp.Namespace = "default"
+			_, _ = forecastleClient.ForecastleV1beta1().ForecastleApps("default").Create(forecastleApp)
+
+			clients := &kube.Clients{
+				KubernetesClient:     kubeClient,
+				ForecastleAppsClient: forecastleClient,
+			}
+
+			cfg := &config.Config{
+				NamespaceSelector: config.NamespaceSelector{Any: true},
+				CRDEnabled:        true,
+				CRDStatusEnabled:  true,
+			}
+
+			handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+			handler.elector = tt.elector
+			if _, err := handler.discoverApps(cfg); err != nil {
+				t.Fatalf("discoverApps() error = %v", err)
+			}
+
+			got, err := forecastleClient.ForecastleV1beta1().ForecastleApps("default").Get("crd-app", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Getting forecastleApp failed: %v", err)
+			}
+			if written := got.Status.ResolvedURL != ""; written != tt.wantWritten {
+				t.Errorf("status written = %v, want %v", written, tt.wantWritten)
+			}
+		})
+	}
+}
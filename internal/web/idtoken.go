@@ -0,0 +1,363 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// providerMetadata is the part of the OpenID Connect discovery document used for login
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint,omitempty"`
+}
+
+// discoverProvider fetches the discovery document of issuerURL and checks that it belongs to the issuer
+func discoverProvider(ctx context.Context, client *http.Client, issuerURL string) (*providerMetadata, error) {
+	var metadata providerMetadata
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuerURL, "/")+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return nil, fmt.Errorf("oidc discovery returned issuer %q, expected %q", metadata.Issuer, issuerURL)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document lacks an authorization, token or jwks endpoint")
+	}
+	return &metadata, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, value any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %v returned %v", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+const (
+	// jwksRefreshInterval limits how often the key set is fetched again for tokens signed with an unknown key
+	jwksRefreshInterval = time.Minute
+	// clockSkew is the difference between the clocks of Forecastle and the provider the time claims tolerate
+	clockSkew = time.Minute
+)
+
+// keySet holds the signing keys of the provider, fetched again when tokens are signed with an unknown key
+type keySet struct {
+	client *http.Client
+	uri    string
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// key returns the key with id kid, or the only key of the set if kid is empty
+func (ks *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	if time.Since(ks.fetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := getJSON(ctx, ks.client, ks.uri, &jwks); err != nil {
+		return nil, fmt.Errorf("unable to fetch signing keys: %w", err)
+	}
+	ks.fetched = time.Now()
+	ks.keys = map[string]crypto.PublicKey{}
+	for _, raw := range jwks.Keys {
+		id, key, err := parseJWK(raw)
+		if err != nil {
+			logger.Debugf("Skipping signing key %q: %v", id, err)
+			continue
+		}
+		ks.keys[id] = key
+	}
+
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (ks *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// parseJWK parses an RSA or EC signing key in JWK format
+func parseJWK(raw json.RawMessage) (string, crypto.PublicKey, error) {
+	var jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	if err := json.Unmarshal(raw, &jwk); err != nil {
+		return "", nil, err
+	}
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return jwk.Kid, nil, fmt.Errorf("key use %q is not sig", jwk.Use)
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return jwk.Kid, nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return jwk.Kid, nil, err
+		}
+		if len(e) == 0 || len(e) > 4 {
+			return jwk.Kid, nil, errors.New("invalid RSA exponent")
+		}
+		return jwk.Kid, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return jwk.Kid, nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return jwk.Kid, nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return jwk.Kid, nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return jwk.Kid, nil, errors.New("invalid EC point")
+		}
+		key, err := ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+		return jwk.Kid, key, err
+	default:
+		return jwk.Kid, nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// idTokenClaims are the claims of an ID token used for login
+type idTokenClaims struct {
+	Issuer            string      `json:"iss"`
+	Subject           string      `json:"sub"`
+	Audience          stringList  `json:"aud"`
+	AuthorizedParty   string      `json:"azp"`
+	Expiry            json.Number `json:"exp"`
+	IssuedAt          json.Number `json:"iat"`
+	NotBefore         json.Number `json:"nbf"`
+	Nonce             string      `json:"nonce"`
+	Email             string      `json:"email"`
+	Name              string      `json:"name"`
+	PreferredUsername string      `json:"preferred_username"`
+	Groups            stringList  `json:"groups"`
+}
+
+// stringList is a claim that is either a string or a list of strings
+type stringList []string
+
+func (s *stringList) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var list []string
+		err := json.Unmarshal(data, &list)
+		*s = list
+		return err
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = stringList{value}
+	return nil
+}
+
+// idTokenVerifier verifies the signature and claims of ID tokens issued to a client
+type idTokenVerifier struct {
+	issuer   string
+	clientID string
+	keys     *keySet
+	now      func() time.Time
+}
+
+// verify returns the claims of the compact serialized ID token rawToken if it is signed by the provider,
+// issued to the client for nonce and valid now
+func (v *idTokenVerifier) verify(ctx context.Context, rawToken string, nonce string) (*idTokenClaims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id token")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed id token header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("malformed id token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed id token signature: %w", err)
+	}
+	key, err := v.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed id token payload: %w", err)
+	}
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed id token payload: %w", err)
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(v.issuer, "/") {
+		return nil, fmt.Errorf("id token issued by %q, expected %q", claims.Issuer, v.issuer)
+	}
+	if !slices.Contains(claims.Audience, v.clientID) {
+		return nil, fmt.Errorf("id token not issued to client %q", v.clientID)
+	}
+	// Tokens for several audiences must name the client as the party they were issued to
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != v.clientID {
+		return nil, fmt.Errorf("id token authorized party %q is not client %q", claims.AuthorizedParty, v.clientID)
+	}
+	now := v.now()
+	expiry, err := numericDate(claims.Expiry)
+	if err != nil || now.After(expiry.Add(clockSkew)) {
+		return nil, errors.New("id token expired")
+	}
+	issuedAt, err := numericDate(claims.IssuedAt)
+	if err != nil || issuedAt.After(now.Add(clockSkew)) {
+		return nil, errors.New("id token has no valid issue time")
+	}
+	if claims.NotBefore != "" {
+		notBefore, err := numericDate(claims.NotBefore)
+		if err != nil || notBefore.After(now.Add(clockSkew)) {
+			return nil, errors.New("id token not valid yet")
+		}
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+	return &claims, nil
+}
+
+// numericDate parses a JWT time claim, in seconds since the epoch
+func numericDate(value json.Number) (time.Time, error) {
+	seconds, err := value.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(seconds), 0), nil
+}
+
+// verifySignature verifies a JWS signature. Only asymmetric algorithms are accepted, so the token can't be
+// signed with a secret known to the client
+func verifySignature(alg string, key crypto.PublicKey, signed []byte, signature []byte) error {
+	if len(alg) != len("RS256") {
+		return fmt.Errorf("unsupported id token algorithm %q", alg)
+	}
+	var hash crypto.Hash
+	// curve is the curve ES algorithms are defined for
+	var curve elliptic.Curve
+	switch alg[len(alg)-3:] {
+	case "256":
+		hash, curve = crypto.SHA256, elliptic.P256()
+	case "384":
+		hash, curve = crypto.SHA384, elliptic.P384()
+	case "512":
+		hash, curve = crypto.SHA512, elliptic.P521()
+	default:
+		return fmt.Errorf("unsupported id token algorithm %q", alg)
+	}
+	hasher := hash.New()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key doesn't match algorithm %q", alg)
+		}
+		if alg[:2] == "PS" {
+			return rsa.VerifyPSS(rsaKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve != curve {
+			return fmt.Errorf("signing key doesn't match algorithm %q", alg)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid id token signature")
+		}
+		r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("invalid id token signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported id token algorithm %q", alg)
+	}
+}
@@ -0,0 +1,49 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// authFailures counts requests rejected for missing or invalid static credentials, by the scope of the
+// credentials (api or ui), the authentication method and the reason
+var authFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "forecastle_auth_failures_total",
+	Help: "Requests rejected for missing or invalid credentials",
+}, []string{"scope", "method", "reason"})
+
+// runMetricsServer serves /metrics on port until ctx is done. Metrics are kept off the dashboard port, so
+// they aren't exposed wherever the dashboard is
+func runMetricsServer(ctx context.Context, port int) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      mux,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Error during metrics server shutdown: ", err)
+		}
+	}()
+
+	logger.Info("Starting metrics server on port ", port)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Metrics server failed: ", err)
+	}
+}
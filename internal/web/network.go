@@ -0,0 +1,87 @@
+package web
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+)
+
+// clientAddr returns the address of the client of r. The X-Forwarded-For header is read from right to left
+// for as long as the address a request was received from belongs to a trusted proxy, so clients can't spoof
+// their address by sending the header themselves. Returns false if the address can't be determined
+func clientAddr(r *http.Request, trustedProxies []netip.Prefix) (netip.Addr, bool) {
+	addr, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0 && containsAddr(trustedProxies, addr); i-- {
+		if addr, ok = parseAddr(strings.TrimSpace(forwarded[i])); !ok {
+			return netip.Addr{}, false
+		}
+	}
+	return addr, true
+}
+
+// fromTrustedProxy returns true if r was received from one of trustedProxies
+func fromTrustedProxy(r *http.Request, trustedProxies []netip.Prefix) bool {
+	addr, ok := parseAddr(r.RemoteAddr)
+	return ok && containsAddr(trustedProxies, addr)
+}
+
+// parseAddr parses an address with an optional port
+func parseAddr(value string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return addr.Unmap(), true
+	}
+	return netip.Addr{}, false
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// appsForClient returns apps as seen by the client of r. Network restricted apps are hidden from, or marked
+// unreachable for, clients outside the internal networks of cfg. apps is returned unchanged for internal
+// clients or when no internal networks are configured
+func appsForClient(r *http.Request, apps []forecastle.App, cfg *config.Config) []forecastle.App {
+	if cfg == nil || len(cfg.ClientNetworks.Internal) == 0 {
+		return apps
+	}
+	internal, trustedProxies, err := cfg.ClientNetworks.Parse()
+	if err != nil {
+		logger.Warn("Ignoring invalid client networks: ", err)
+		return apps
+	}
+	if addr, ok := clientAddr(r, trustedProxies); ok && containsAddr(internal, addr) {
+		return apps
+	}
+
+	var visible []forecastle.App
+	for _, app := range apps {
+		if !app.NetworkRestricted {
+			visible = append(visible, app)
+			continue
+		}
+		if cfg.ClientNetworks.Mode == config.ClientNetworkModeMark {
+			app.Unreachable = true
+			visible = append(visible, app)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,204 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	forecastlefake "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/fake"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClientAddr(t *testing.T) {
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")}
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+		wantUnknown  bool
+	}{
+		{
+			name:       "DirectClient",
+			remoteAddr: "203.0.113.7:51234",
+			want:       "203.0.113.7",
+		},
+		{
+			name:         "UntrustedRemoteIgnoresForwardedFor",
+			remoteAddr:   "203.0.113.7:51234",
+			forwardedFor: []string{"10.1.2.3"},
+			want:         "203.0.113.7",
+		},
+		{
+			name:         "TrustedProxy",
+			remoteAddr:   "10.0.0.5:8080",
+			forwardedFor: []string{"198.51.100.4"},
+			want:         "198.51.100.4",
+		},
+		{
+			name:         "SpoofedEntryLeftOfClient",
+			remoteAddr:   "10.0.0.5:8080",
+			forwardedFor: []string{"10.9.9.9, 198.51.100.4, 10.0.0.6"},
+			want:         "198.51.100.4",
+		},
+		{
+			name:         "MultipleHeaders",
+			remoteAddr:   "[fd00::1]:8080",
+			forwardedFor: []string{"198.51.100.4", "10.0.0.6:4711"},
+			want:         "198.51.100.4",
+		},
+		{
+			name:         "IPv4MappedRemote",
+			remoteAddr:   "[::ffff:10.0.0.5]:8080",
+			forwardedFor: []string{"[2001:db8::1]:443"},
+			want:         "2001:db8::1",
+		},
+		{
+			name:         "AllTrusted",
+			remoteAddr:   "10.0.0.5:8080",
+			forwardedFor: []string{"10.0.0.7, 10.0.0.6"},
+			want:         "10.0.0.7",
+		},
+		{
+			name:         "MalformedForwardedFor",
+			remoteAddr:   "10.0.0.5:8080",
+			forwardedFor: []string{"unknown"},
+			wantUnknown:  true,
+		},
+		{
+			name:        "MalformedRemoteAddr",
+			remoteAddr:  "pipe",
+			wantUnknown: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/apps", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			got, ok := clientAddr(req, trustedProxies)
+			if ok == tt.wantUnknown {
+				t.Fatalf("clientAddr() ok = %v, want %v", ok, !tt.wantUnknown)
+			}
+			if ok && got.String() != tt.want {
+				t.Errorf("clientAddr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_AppsHandler_ClientNetworks(t *testing.T) {
+	clients := &kube.Clients{
+		KubernetesClient:     fake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+		ForecastleAppsClient: forecastlefake.NewSimpleClientset(),
+	}
+	customApps := []config.CustomApp{
+		{Name: "public", Group: "apps", URL: "https://public.example.com"},
+		{Name: "vpn-only", Group: "apps", URL: "https://vpn-only.example.com", NetworkRestricted: true},
+	}
+
+	tests := []struct {
+		name            string
+		networks        config.ClientNetworks
+		remoteAddr      string
+		forwardedFor    string
+		want            []string
+		wantUnreachable []string
+	}{
+		{
+			name:       "NoInternalNetworks",
+			remoteAddr: "203.0.113.7:51234",
+			want:       []string{"public", "vpn-only"},
+		},
+		{
+			name:       "InternalClient",
+			networks:   config.ClientNetworks{Internal: []string{"192.168.0.0/16"}},
+			remoteAddr: "192.168.1.20:51234",
+			want:       []string{"public", "vpn-only"},
+		},
+		{
+			name:       "ExternalClientHidden",
+			networks:   config.ClientNetworks{Internal: []string{"192.168.0.0/16"}},
+			remoteAddr: "203.0.113.7:51234",
+			want:       []string{"public"},
+		},
+		{
+			name:            "ExternalClientMarked",
+			networks:        config.ClientNetworks{Internal: []string{"192.168.0.0/16"}, Mode: config.ClientNetworkModeMark},
+			remoteAddr:      "203.0.113.7:51234",
+			want:            []string{"public", "vpn-only"},
+			wantUnreachable: []string{"vpn-only"},
+		},
+		{
+			name:         "InternalClientBehindTrustedProxy",
+			networks:     config.ClientNetworks{Internal: []string{"192.168.0.0/16"}, TrustedProxies: []string{"10.0.0.5"}},
+			remoteAddr:   "10.0.0.5:8080",
+			forwardedFor: "192.168.1.20",
+			want:         []string{"public", "vpn-only"},
+		},
+		{
+			name:         "SpoofedForwardedFor",
+			networks:     config.ClientNetworks{Internal: []string{"192.168.0.0/16"}},
+			remoteAddr:   "203.0.113.7:51234",
+			forwardedFor: "192.168.1.20",
+			want:         []string{"public"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				NamespaceSelector: config.NamespaceSelector{Any: true},
+				CustomApps:        customApps,
+				ClientNetworks:    tt.networks,
+			}
+			handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+			handler.refreshCache(context.Background())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/apps", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			handler.AppsHandler(rec, req)
+
+			var apps []forecastle.App
+			if err := json.NewDecoder(rec.Body).Decode(&apps); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var names, unreachable []string
+			for _, app := range apps {
+				names = append(names, app.Name)
+				if app.Unreachable {
+					unreachable = append(unreachable, app.Name)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Expected apps %v, got %v", tt.want, names)
+			}
+			if !reflect.DeepEqual(unreachable, tt.wantUnreachable) {
+				t.Errorf("Expected unreachable apps %v, got %v", tt.wantUnreachable, unreachable)
+			}
+
+			// The cache must not be modified by marking apps for one client
+			handler.appsCacheMu.RLock()
+			defer handler.appsCacheMu.RUnlock()
+			for _, app := range handler.appsCache {
+				if app.Unreachable {
+					t.Errorf("Expected cached app %q not to be marked unreachable", app.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// OIDCCallbackPath is the path the provider redirects to after login, relative to the base path
+	OIDCCallbackPath = "/oauth2/callback"
+	// OIDCLogoutPath ends the session and logs out of the provider, relative to the base path
+	OIDCLogoutPath = "/oauth2/logout"
+
+	sessionCookieName = "forecastle_session"
+	loginCookieName   = "forecastle_login"
+	// loginTimeout is how long users have to log in at the provider
+	loginTimeout = 10 * time.Minute
+	// maxCookieSize is the size of the cookies browsers are required to store, name and value included.
+	// Larger cookies are dropped by browsers and proxies, which would loop users through the login
+	maxCookieSize = 4096
+)
+
+// openPaths are served without login, so probes keep working
+var openPaths = []string{"/healthz", "/readyz"}
+
+// loginState is kept in an encrypted cookie while the user logs in at the provider
+type loginState struct {
+	State    string    `json:"state"`
+	Nonce    string    `json:"nonce"`
+	Verifier string    `json:"verifier"`
+	Redirect string    `json:"redirect"`
+	Expiry   time.Time `json:"exp"`
+}
+
+// oidcAuthenticator logs users in with the authorization code flow with PKCE of an OpenID Connect provider
+type oidcAuthenticator struct {
+	cfg          config.OIDC
+	clientSecret string
+	codec        *cookieCodec
+	client       *http.Client
+	now          func() time.Time
+	// trustedProxies are the proxies whose X-Forwarded-Host and X-Forwarded-Proto headers are honored
+	trustedProxies []netip.Prefix
+
+	mu       sync.Mutex
+	provider *providerMetadata
+	verifier *idTokenVerifier
+}
+
+// OIDCMiddleware requires users to log in with the OpenID Connect provider of cfg. Requests without a valid
+// session are redirected to the provider, or rejected with 401 for /api/ paths. It serves the callback and
+// logout endpoints and puts the session in the request context. The provider is discovered on first use,
+// so the dashboard starts while the provider is unavailable. URLs not set in cfg are derived from the
+// request, honoring the forwarded headers of trustedProxies. Must run after BasePathMiddleware
+func OIDCMiddleware(cfg config.OIDC, trustedProxies []netip.Prefix) (func(http.Handler) http.Handler, error) {
+	authenticator, err := newOIDCAuthenticator(cfg, trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return authenticator.middleware, nil
+}
+
+func newOIDCAuthenticator(cfg config.OIDC, trustedProxies []netip.Prefix) (*oidcAuthenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	clientSecret, err := cfg.GetClientSecret()
+	if err != nil {
+		return nil, err
+	}
+	cookieSecret, err := cfg.GetCookieSecret()
+	if err != nil {
+		return nil, err
+	}
+	if cookieSecret == "" {
+		logger.Warn("No OIDC cookie secret configured, sessions won't survive restarts or be shared between replicas")
+	}
+	if cfg.RedirectURL == "" && len(trustedProxies) == 0 {
+		logger.Warn("No OIDC redirect URL or trusted proxies configured, the redirect URL is derived from the Host header")
+	}
+	codec, err := newCookieCodec(cookieSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &oidcAuthenticator{
+		cfg:          cfg,
+		clientSecret: clientSecret,
+		codec:        codec,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		now:          time.Now,
+
+		trustedProxies: trustedProxies,
+	}, nil
+}
+
+func (a *oidcAuthenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path := r.URL.Path; {
+		case slices.Contains(openPaths, path):
+			next.ServeHTTP(w, r)
+		case path == OIDCCallbackPath:
+			a.callback(w, r)
+		case path == OIDCLogoutPath:
+			a.logout(w, r)
+		case getStaticUser(r) != nil:
+			next.ServeHTTP(w, r)
+		default:
+			session := a.session(r)
+			if session == nil {
+				if strings.HasPrefix(path, "/api/") {
+					http.Error(w, "login required", http.StatusUnauthorized)
+					return
+				}
+				a.login(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionContextKey, session)))
+		}
+	})
+}
+
+// getProvider returns the discovered provider, discovering it if it isn't yet
+func (a *oidcAuthenticator) getProvider(ctx context.Context) (*providerMetadata, *idTokenVerifier, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.provider == nil {
+		provider, err := discoverProvider(ctx, a.client, a.cfg.IssuerURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		a.provider = provider
+		a.verifier = &idTokenVerifier{
+			issuer:   provider.Issuer,
+			clientID: a.cfg.ClientID,
+			keys:     &keySet{client: a.client, uri: provider.JWKSURI},
+			now:      a.now,
+		}
+	}
+	return a.provider, a.verifier, nil
+}
+
+func (a *oidcAuthenticator) oauth2Config(provider *providerMetadata, r *http.Request) *oauth2.Config {
+	redirectURL := a.cfg.RedirectURL
+	if redirectURL == "" {
+		redirectURL = a.externalURL(r, OIDCCallbackPath)
+	}
+	scopes := a.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"profile", "email"}
+	}
+	return &oauth2.Config{
+		ClientID:     a.cfg.ClientID,
+		ClientSecret: a.clientSecret,
+		Endpoint:     oauth2.Endpoint{AuthURL: provider.AuthorizationEndpoint, TokenURL: provider.TokenEndpoint},
+		RedirectURL:  redirectURL,
+		Scopes:       append([]string{"openid"}, slices.DeleteFunc(slices.Clone(scopes), func(s string) bool { return s == "openid" })...),
+	}
+}
+
+// session returns the valid session of the request, nil if there is none
+func (a *oidcAuthenticator) session(r *http.Request) *Session {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return nil
+	}
+	var session Session
+	if err := a.codec.decode(sessionCookieName, cookie.Value, &session); err != nil {
+		logger.Debug("Ignoring invalid session cookie: ", err)
+		return nil
+	}
+	if a.now().After(session.Expiry) {
+		return nil
+	}
+	return &session
+}
+
+// login redirects to the provider, remembering the requested page to return to after login
+func (a *oidcAuthenticator) login(w http.ResponseWriter, r *http.Request) {
+	provider, _, err := a.getProvider(r.Context())
+	if err != nil {
+		logger.Error("Unable to start login: ", err)
+		http.Error(w, "login provider unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	state := loginState{
+		State:    rand.Text(),
+		Nonce:    rand.Text(),
+		Verifier: oauth2.GenerateVerifier(),
+		Redirect: GetBasePath(r) + r.URL.RequestURI(),
+		Expiry:   a.now().Add(loginTimeout),
+	}
+	if err := a.setCookie(w, r, loginCookieName, state, state.Expiry); err != nil {
+		logger.Error("Unable to start login: ", err)
+		http.Error(w, "unable to start login", http.StatusInternalServerError)
+		return
+	}
+
+	authURL := a.oauth2Config(provider, r).AuthCodeURL(state.State,
+		oauth2.S256ChallengeOption(state.Verifier),
+		oauth2.SetAuthURLParam("nonce", state.Nonce))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// callback completes the login, exchanging the authorization code for an ID token that starts the session
+func (a *oidcAuthenticator) callback(w http.ResponseWriter, r *http.Request) {
+	var state loginState
+	cookie, err := r.Cookie(loginCookieName)
+	if err == nil {
+		err = a.codec.decode(loginCookieName, cookie.Value, &state)
+	}
+	a.clearCookie(w, r, loginCookieName)
+	if err != nil || a.now().After(state.Expiry) {
+		http.Error(w, "login expired, please try again", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	if errorCode := query.Get("error"); errorCode != "" {
+		logger.Warnf("Login failed at the provider: %v %v", errorCode, query.Get("error_description"))
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state.State)) != 1 {
+		http.Error(w, "invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	provider, verifier, err := a.getProvider(r.Context())
+	if err != nil {
+		logger.Error("Unable to complete login: ", err)
+		http.Error(w, "login provider unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), oauth2.HTTPClient, a.client)
+	token, err := a.oauth2Config(provider, r).Exchange(ctx, query.Get("code"), oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		logger.Error("Unable to exchange authorization code: ", err)
+		http.Error(w, "login failed", http.StatusBadGateway)
+		return
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		logger.Error("Token response of the provider has no id_token")
+		http.Error(w, "login failed", http.StatusBadGateway)
+		return
+	}
+	claims, err := verifier.verify(r.Context(), rawIDToken, state.Nonce)
+	if err != nil {
+		logger.Warn("Rejected id token: ", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	session := Session{
+		Subject: claims.Subject,
+		Email:   claims.Email,
+		Name:    claims.Name,
+		Groups:  claims.Groups,
+		Expiry:  a.now().Add(a.cfg.GetSessionDuration()),
+	}
+	if session.Name == "" {
+		session.Name = claims.PreferredUsername
+	}
+	switch a.cfg.UsernameClaim {
+	case "email":
+		session.Username = claims.Email
+	case "preferred_username":
+		session.Username = claims.PreferredUsername
+	}
+	if session.Username == "" {
+		session.Username = claims.Subject
+	}
+	if err := a.setCookie(w, r, sessionCookieName, session, session.Expiry); err != nil {
+		logger.Errorf("Unable to start session of user '%v' with %d groups: %v. Limit the groups the provider puts in the id token",
+			session.Username, len(session.Groups), err)
+		http.Error(w, "login failed", http.StatusInternalServerError)
+		return
+	}
+	logger.Infof("User '%v' logged in", session.Username)
+
+	redirect := state.Redirect
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") {
+		redirect = GetBasePath(r) + "/"
+	}
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+// logout ends the session, and the session at the provider if it supports RP-initiated logout. Only POST
+// requests of the dashboard itself are accepted, so other sites can't log users out with a link or an image
+func (a *oidcAuthenticator) logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" && site != "same-origin" {
+		http.Error(w, "cross-site logout", http.StatusForbidden)
+		return
+	}
+	a.clearCookie(w, r, sessionCookieName)
+
+	postLogoutURL := a.cfg.PostLogoutRedirectURL
+	if postLogoutURL == "" {
+		postLogoutURL = a.externalURL(r, "/")
+	}
+
+	provider, _, err := a.getProvider(r.Context())
+	if err != nil || provider.EndSessionEndpoint == "" {
+		http.Redirect(w, r, postLogoutURL, http.StatusFound)
+		return
+	}
+	endSessionURL, err := url.Parse(provider.EndSessionEndpoint)
+	if err != nil {
+		http.Redirect(w, r, postLogoutURL, http.StatusFound)
+		return
+	}
+	query := endSessionURL.Query()
+	query.Set("client_id", a.cfg.ClientID)
+	query.Set("post_logout_redirect_uri", postLogoutURL)
+	endSessionURL.RawQuery = query.Encode()
+	http.Redirect(w, r, endSessionURL.String(), http.StatusFound)
+}
+
+func (a *oidcAuthenticator) setCookie(w http.ResponseWriter, r *http.Request, name string, value any, expiry time.Time) error {
+	encoded, err := a.codec.encode(name, value)
+	if err != nil {
+		return err
+	}
+	if size := len(name) + len("=") + len(encoded); size > maxCookieSize {
+		return fmt.Errorf("%v cookie of %d bytes exceeds the %d bytes browsers store", name, size, maxCookieSize)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    encoded,
+		Path:     cookiePath(r),
+		Expires:  expiry,
+		Secure:   a.isHTTPS(r),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+func (a *oidcAuthenticator) clearCookie(w http.ResponseWriter, r *http.Request, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Path:     cookiePath(r),
+		MaxAge:   -1,
+		Secure:   a.isHTTPS(r),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func cookiePath(r *http.Request) string {
+	if basePath := GetBasePath(r); basePath != "" {
+		return basePath
+	}
+	return "/"
+}
+
+// isHTTPS returns true if the client reached the dashboard over HTTPS, as told by X-Forwarded-Proto of
+// trusted proxies
+func (a *oidcAuthenticator) isHTTPS(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return fromTrustedProxy(r, a.trustedProxies) && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// externalURL returns the URL of path under the base path as seen by the client. The X-Forwarded-Proto and
+// X-Forwarded-Host headers are only honored from trusted proxies, so clients can't have the provider redirect
+// to hosts of their choosing
+func (a *oidcAuthenticator) externalURL(r *http.Request, path string) string {
+	scheme := "http"
+	if a.isHTTPS(r) {
+		scheme = "https"
+	}
+	host := r.Host
+	if forwardedHost := r.Header.Get("X-Forwarded-Host"); forwardedHost != "" && fromTrustedProxy(r, a.trustedProxies) {
+		host = forwardedHost
+	}
+	return scheme + "://" + host + GetBasePath(r) + path
+}
@@ -0,0 +1,563 @@
+package web
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+)
+
+// mockIdP is a minimal OpenID Connect provider issuing RS256 ID tokens for a single authorization code
+type mockIdP struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu sync.Mutex
+	// claims are added to the ID tokens issued
+	claims        map[string]any
+	codeChallenge string
+	nonce         string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key, claims: map[string]any{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(providerMetadata{
+			Issuer:                idp.URL,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			JWKSURI:               idp.URL + "/jwks",
+			EndSessionEndpoint:    idp.URL + "/logout",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "forecastle" || clientSecret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		verifier := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("code") != "code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != idp.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := map[string]any{
+			"iss":   idp.URL,
+			"sub":   "user-1",
+			"aud":   "forecastle",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": idp.nonce,
+			"email": "jane@example.com",
+			"name":  "Jane",
+		}
+		for key, value := range idp.claims {
+			claims[key] = value
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     signRS256(t, key, "test", claims),
+		})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// authorize plays the user logging in at the provider, returning the callback URL the provider redirects to
+func (idp *mockIdP) authorize(t *testing.T, authURL string) string {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != "forecastle" ||
+		!strings.Contains(query.Get("scope"), "openid") {
+		t.Fatalf("Unexpected authorization request %v", authURL)
+	}
+
+	idp.mu.Lock()
+	idp.codeChallenge = query.Get("code_challenge")
+	idp.nonce = query.Get("nonce")
+	idp.mu.Unlock()
+
+	return query.Get("redirect_uri") + "?" + url.Values{"code": {"code"}, "state": {query.Get("state")}}.Encode()
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// newOIDCTestServer serves a protected dashboard behind the OIDC middleware, recording the session of requests
+func newOIDCTestServer(t *testing.T, idp *mockIdP, basePath string) (*httptest.Server, *Session) {
+	t.Helper()
+	t.Setenv(config.OIDCClientSecretEnv, "client-secret")
+	t.Setenv(config.OIDCCookieSecretEnv, "cookie-secret")
+	oidcMiddleware, err := OIDCMiddleware(config.OIDC{Enabled: true, IssuerURL: idp.URL, ClientID: "forecastle"}, nil)
+	if err != nil {
+		t.Fatalf("OIDCMiddleware() error = %v", err)
+	}
+
+	seen := &Session{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if session := GetSession(r); session != nil {
+			*seen = *session
+		}
+		_, _ = w.Write([]byte("dashboard"))
+	})
+	server := httptest.NewServer(ChainMiddleware(mux, BasePathMiddleware(basePath), oidcMiddleware))
+	t.Cleanup(server.Close)
+	return server, seen
+}
+
+// noRedirectClient returns a client that keeps cookies and doesn't follow redirects
+func noRedirectClient(t *testing.T) *http.Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &http.Client{Jar: jar, CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+}
+
+func get(t *testing.T, client *http.Client, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return do(t, client, req)
+}
+
+func do(t *testing.T, client *http.Client, req *http.Request) *http.Response {
+	t.Helper()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestOIDCMiddleware_LoginFlow(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims["groups"] = []string{"admins", "devs"}
+	server, seen := newOIDCTestServer(t, idp, "/forecastle")
+	client := noRedirectClient(t)
+
+	// Unauthenticated page requests are redirected to the provider
+	resp := get(t, client, server.URL+"/forecastle/apps?tag=ops")
+	if resp.StatusCode != http.StatusFound || !strings.HasPrefix(resp.Header.Get("Location"), idp.URL+"/authorize") {
+		t.Fatalf("Expected redirect to the provider, got %d %v", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	callbackURL := idp.authorize(t, resp.Header.Get("Location"))
+	if !strings.HasPrefix(callbackURL, server.URL+"/forecastle/oauth2/callback?") {
+		t.Fatalf("Expected the redirect URI to be derived from the request, got %v", callbackURL)
+	}
+
+	// The callback starts the session and returns to the requested page
+	resp = get(t, client, callbackURL)
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/forecastle/apps?tag=ops" {
+		t.Fatalf("Expected redirect to the requested page, got %d %v", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == sessionCookieName && (!cookie.HttpOnly || cookie.Path != "/forecastle" || strings.Contains(cookie.Value, "jane")) {
+			t.Errorf("Expected an encrypted HttpOnly session cookie on the base path, got %+v", cookie)
+		}
+	}
+
+	resp = get(t, client, server.URL+"/forecastle/api/apps")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected logged in request to succeed, got %d", resp.StatusCode)
+	}
+	if seen.Subject != "user-1" || seen.Email != "jane@example.com" || seen.Name != "Jane" || len(seen.Groups) != 2 {
+		t.Errorf("Expected the session of jane in the request context, got %+v", seen)
+	}
+
+	// Logout is refused for GET and cross-site requests, so other sites can't log users out
+	if resp = get(t, client, server.URL+"/forecastle/oauth2/logout"); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET logout to be refused, got %d", resp.StatusCode)
+	}
+	crossSite, _ := http.NewRequest(http.MethodPost, server.URL+"/forecastle/oauth2/logout", nil)
+	crossSite.Header.Set("Sec-Fetch-Site", "cross-site")
+	if resp = do(t, client, crossSite); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected cross-site logout to be refused, got %d", resp.StatusCode)
+	}
+	if resp = get(t, client, server.URL+"/forecastle/api/apps"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the session to survive refused logouts, got %d", resp.StatusCode)
+	}
+
+	// Logout ends the session and the session at the provider
+	logout, _ := http.NewRequest(http.MethodPost, server.URL+"/forecastle/oauth2/logout", nil)
+	logout.Header.Set("Sec-Fetch-Site", "same-origin")
+	resp = do(t, client, logout)
+	location, _ := url.Parse(resp.Header.Get("Location"))
+	if resp.StatusCode != http.StatusFound || !strings.HasPrefix(location.String(), idp.URL+"/logout") ||
+		location.Query().Get("post_logout_redirect_uri") != server.URL+"/forecastle/" {
+		t.Fatalf("Expected redirect to the end session endpoint, got %d %v", resp.StatusCode, location)
+	}
+	resp = get(t, client, server.URL+"/forecastle/api/apps")
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected API request after logout to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestOIDCMiddleware_OpenPathsAndAPI(t *testing.T) {
+	idp := newMockIdP(t)
+	server, _ := newOIDCTestServer(t, idp, "")
+	client := noRedirectClient(t)
+
+	for _, path := range openPaths {
+		if resp := get(t, client, server.URL+path); resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected %v to be served without login, got %d", path, resp.StatusCode)
+		}
+	}
+	if resp := get(t, client, server.URL+"/api/apps"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected API request without session to be rejected, got %d", resp.StatusCode)
+	}
+
+	// A forged session cookie is ignored
+	forged, _ := url.Parse(server.URL)
+	client.Jar.SetCookies(forged, []*http.Cookie{{Name: sessionCookieName, Value: base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`))}})
+	if resp := get(t, client, server.URL+"/api/apps"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected API request with forged session to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestOIDCAuthenticator_ExternalURL(t *testing.T) {
+	authenticator := &oidcAuthenticator{trustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       string
+	}{
+		{name: "TrustedProxy", remoteAddr: "10.0.0.1:1234", want: "https://forecastle.example.com/oauth2/callback"},
+		{name: "UntrustedClient", remoteAddr: "192.0.2.1:1234", want: "http://forecastle.internal/oauth2/callback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://forecastle.internal/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set("X-Forwarded-Host", "forecastle.example.com")
+			r.Header.Set("X-Forwarded-Proto", "https")
+			if got := authenticator.externalURL(r, OIDCCallbackPath); got != tt.want {
+				t.Errorf("externalURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOIDCMiddleware_RejectsInvalidCallbacks(t *testing.T) {
+	tests := []struct {
+		name       string
+		callback   func(callbackURL string) string
+		claims     map[string]any
+		wantStatus int
+	}{
+		{
+			name: "StateMismatch",
+			callback: func(callbackURL string) string {
+				return strings.Split(callbackURL, "?")[0] + "?code=code&state=forged"
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "ProviderError",
+			callback: func(callbackURL string) string {
+				return strings.Split(callbackURL, "?")[0] + "?error=access_denied"
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "WrongAudience",
+			callback:   func(callbackURL string) string { return callbackURL },
+			claims:     map[string]any{"aud": "another-client"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "WrongNonce",
+			callback:   func(callbackURL string) string { return callbackURL },
+			claims:     map[string]any{"nonce": "replayed"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "ExpiredToken",
+			callback:   func(callbackURL string) string { return callbackURL },
+			claims:     map[string]any{"exp": time.Now().Add(-time.Hour).Unix()},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "SessionCookieTooLarge",
+			callback:   func(callbackURL string) string { return callbackURL },
+			claims:     map[string]any{"groups": manyGroups(200)},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockIdP(t)
+			for key, value := range tt.claims {
+				idp.claims[key] = value
+			}
+			server, _ := newOIDCTestServer(t, idp, "")
+			client := noRedirectClient(t)
+
+			resp := get(t, client, server.URL+"/")
+			callbackURL := idp.authorize(t, resp.Header.Get("Location"))
+			if resp = get(t, client, tt.callback(callbackURL)); resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected callback to fail with %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if resp := get(t, client, server.URL+"/api/apps"); resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("Expected no session after a failed login, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+// manyGroups returns count random group names, which the session cookie can't compress
+func manyGroups(count int) []string {
+	groups := make([]string, count)
+	for i := range groups {
+		groups[i] = rand.Text()
+	}
+	return groups
+}
+
+func TestOIDCMiddleware_CallbackWithoutLogin(t *testing.T) {
+	idp := newMockIdP(t)
+	server, _ := newOIDCTestServer(t, idp, "")
+	if resp := get(t, noRedirectClient(t), server.URL+OIDCCallbackPath+"?code=code&state=state"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected callback without login state to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+// newECVerifier returns a verifier of tokens signed with the keys of the provider, served by kid with their
+// JWK curve name, and a function signing claims with the key of kid
+func newECVerifier(t *testing.T, keys map[string]*ecdsa.PrivateKey) (*idTokenVerifier, func(alg string, kid string, claims map[string]any) string) {
+	t.Helper()
+	jwk := []map[string]string{{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"}}
+	for kid, key := range keys {
+		publicKey, err := key.PublicKey.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := (len(publicKey) - 1) / 2
+		jwk = append(jwk, map[string]string{
+			"kty": "EC",
+			"kid": kid,
+			"crv": key.Curve.Params().Name,
+			"x":   base64.RawURLEncoding.EncodeToString(publicKey[1 : 1+size]),
+			"y":   base64.RawURLEncoding.EncodeToString(publicKey[1+size:]),
+		})
+	}
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": jwk})
+	}))
+	t.Cleanup(jwks.Close)
+
+	sign := func(alg string, kid string, claims map[string]any) string {
+		key := keys[kid]
+		header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid})
+		payload, _ := json.Marshal(claims)
+		signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		digest := sha256.Sum256([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature := append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+		return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	return &idTokenVerifier{
+		issuer:   "https://idp.example.com",
+		clientID: "forecastle",
+		keys:     &keySet{client: jwks.Client(), uri: jwks.URL},
+		now:      time.Now,
+	}, sign
+}
+
+func TestIDTokenVerifier_ES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, sign := newECVerifier(t, map[string]*ecdsa.PrivateKey{"ec": key})
+	claims := map[string]any{
+		"iss":    "https://idp.example.com/",
+		"sub":    "user-1",
+		"aud":    []string{"other", "forecastle"},
+		"azp":    "forecastle",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"iat":    time.Now().Unix(),
+		"nonce":  "nonce",
+		"groups": "admins",
+	}
+
+	got, err := verifier.verify(context.Background(), sign("ES256", "ec", claims), "nonce")
+	if err != nil {
+		t.Fatalf("verify() error = %v", err)
+	}
+	if got.Subject != "user-1" || len(got.Groups) != 1 || got.Groups[0] != "admins" {
+		t.Errorf("verify() = %+v, want user-1 in group admins", got)
+	}
+
+	if _, err := verifier.verify(context.Background(), sign("HS256", "ec", claims), "nonce"); err == nil {
+		t.Error("verify() accepted a token with a symmetric algorithm")
+	}
+	tampered := sign("ES256", "ec", claims)
+	tampered = tampered[:len(tampered)-4] + "AAAA"
+	if _, err := verifier.verify(context.Background(), tampered, "nonce"); err == nil {
+		t.Error("verify() accepted a token with a tampered signature")
+	}
+}
+
+func TestIDTokenVerifier_RejectsCurveOfOtherAlgorithm(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, sign := newECVerifier(t, map[string]*ecdsa.PrivateKey{"p384": key})
+	claims := map[string]any{
+		"iss":   "https://idp.example.com",
+		"sub":   "user-1",
+		"aud":   "forecastle",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": "nonce",
+	}
+
+	if _, err := verifier.verify(context.Background(), sign("ES256", "p384", claims), "nonce"); err == nil {
+		t.Error("verify() accepted an ES256 token signed with a P-384 key")
+	}
+}
+
+func TestIDTokenVerifier_Claims(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, sign := newECVerifier(t, map[string]*ecdsa.PrivateKey{"ec": key})
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		claims  map[string]any
+		wantErr bool
+	}{
+		{name: "Valid", claims: map[string]any{}},
+		{name: "AuthorizedPartyOfClient", claims: map[string]any{"aud": []string{"forecastle", "api"}, "azp": "forecastle"}},
+		{name: "SeveralAudiencesWithoutAuthorizedParty", claims: map[string]any{"aud": []string{"forecastle", "api"}}, wantErr: true},
+		{name: "AuthorizedPartyOfOtherClient", claims: map[string]any{"azp": "other"}, wantErr: true},
+		{name: "OtherAudience", claims: map[string]any{"aud": "other"}, wantErr: true},
+		{name: "Expired", claims: map[string]any{"exp": now.Add(-2 * time.Minute).Unix()}, wantErr: true},
+		{name: "ExpiredWithinClockSkew", claims: map[string]any{"exp": now.Add(-30 * time.Second).Unix()}},
+		{name: "MissingIssueTime", claims: map[string]any{"iat": nil}, wantErr: true},
+		{name: "IssuedInFuture", claims: map[string]any{"iat": now.Add(5 * time.Minute).Unix()}, wantErr: true},
+		{name: "NotValidYet", claims: map[string]any{"nbf": now.Add(5 * time.Minute).Unix()}, wantErr: true},
+		{name: "ValidSince", claims: map[string]any{"nbf": now.Add(-time.Minute).Unix()}},
+		{name: "OtherNonce", claims: map[string]any{"nonce": "other"}, wantErr: true},
+		{name: "OtherIssuer", claims: map[string]any{"iss": "https://other.example.com"}, wantErr: true},
+		{name: "MissingSubject", claims: map[string]any{"sub": ""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := map[string]any{
+				"iss":   "https://idp.example.com",
+				"sub":   "user-1",
+				"aud":   "forecastle",
+				"exp":   now.Add(time.Hour).Unix(),
+				"iat":   now.Unix(),
+				"nonce": "nonce",
+			}
+			for key, value := range tt.claims {
+				if value == nil {
+					delete(claims, key)
+					continue
+				}
+				claims[key] = value
+			}
+			_, err := verifier.verify(context.Background(), sign("ES256", "ec", claims), "nonce")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCookieCodec(t *testing.T) {
+	codec, err := newCookieCodec("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := codec.encode(sessionCookieName, Session{Subject: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var session Session
+	if err := codec.decode(sessionCookieName, encoded, &session); err != nil || session.Subject != "user-1" {
+		t.Errorf("decode() = %+v, %v, want user-1", session, err)
+	}
+	if err := codec.decode(loginCookieName, encoded, &session); err == nil {
+		t.Error("decode() accepted a value encoded for another cookie")
+	}
+	other, _ := newCookieCodec("other-secret")
+	if err := other.decode(sessionCookieName, encoded, &session); err == nil {
+		t.Error("decode() accepted a value encrypted with another secret")
+	}
+}
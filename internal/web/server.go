@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/netip"
 	"time"
 
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/kube/leader"
 )
 
 // ServerConfig holds configuration for the web server
@@ -17,6 +19,17 @@ type ServerConfig struct {
 	Port          int
 	CacheInterval time.Duration
 	BasePath      string
+	// MetricsPort serves /metrics apart from the dashboard, 0 to disable metrics
+	MetricsPort int
+	// LeaderElection elects the replica that writes ForecastleApp statuses, nil to disable leader election
+	LeaderElection *leader.Config
+	// OIDC requires users to log in with an OpenID Connect provider, nil to disable login
+	OIDC *config.OIDC
+	// TrustedProxies are the proxies whose X-Forwarded-Host and X-Forwarded-Proto headers OIDC login honors
+	TrustedProxies []netip.Prefix
+	// APIAuth and UIAuth require static credentials for /api/* and the dashboard, disabled if empty
+	APIAuth config.StaticAuth
+	UIAuth  config.StaticAuth
 }
 
 // DefaultServerConfig returns default server configuration
@@ -32,13 +45,25 @@ func DefaultServerConfig() ServerConfig {
 func RunServer(ctx context.Context, clients *kube.Clients, cfg ServerConfig) error {
 	// Create handler with background caching
 	handler := NewHandler(clients, config.GetConfig, cfg.CacheInterval)
+	if cfg.LeaderElection != nil {
+		elector, err := leader.Run(ctx, clients.KubernetesClient, *cfg.LeaderElection)
+		if err != nil {
+			return fmt.Errorf("failed to start leader election: %w", err)
+		}
+		handler.elector = elector
+	}
 	handler.StartBackgroundCache(ctx)
+	if cfg.MetricsPort != 0 {
+		go runMetricsServer(ctx, cfg.MetricsPort)
+	}
 
 	// Create router
 	mux := http.NewServeMux()
 
 	// API routes
 	mux.HandleFunc("GET /api/apps", handler.AppsHandler)
+	mux.HandleFunc("GET /api/apps/{id}", handler.AppHandler)
+	mux.HandleFunc("GET /api/groups", handler.GroupsHandler)
 	mux.HandleFunc("GET /api/config", handler.ConfigHandler)
 
 	// Health endpoints
@@ -84,14 +109,27 @@ func RunServer(ctx context.Context, clients *kube.Clients, cfg ServerConfig) err
 	})
 
 	// Apply middleware stack
-	wrapped := ChainMiddleware(mux,
+	middlewares := []func(http.Handler) http.Handler{
 		BasePathMiddleware(cfg.BasePath),
 		LoggingMiddleware,
 		SecurityHeadersMiddleware,
-		CORSMiddleware,
-		CacheControlMiddleware,
-		GzipMiddleware,
-	)
+	}
+	if cfg.APIAuth.IsEnabled() || cfg.UIAuth.IsEnabled() {
+		staticAuthMiddleware, err := StaticAuthMiddleware(cfg.APIAuth, cfg.UIAuth, cfg.OIDC != nil)
+		if err != nil {
+			return fmt.Errorf("failed to configure static authentication: %w", err)
+		}
+		middlewares = append(middlewares, staticAuthMiddleware)
+	}
+	if cfg.OIDC != nil {
+		oidcMiddleware, err := OIDCMiddleware(*cfg.OIDC, cfg.TrustedProxies)
+		if err != nil {
+			return fmt.Errorf("failed to configure OIDC login: %w", err)
+		}
+		middlewares = append(middlewares, oidcMiddleware)
+	}
+	middlewares = append(middlewares, CORSMiddleware, CacheControlMiddleware, GzipMiddleware)
+	wrapped := ChainMiddleware(mux, middlewares...)
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
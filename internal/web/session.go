@@ -0,0 +1,94 @@
+package web
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// cookieCodec encrypts values into cookies with AES-GCM, so their contents can neither be read nor forged
+// by clients. The cookie name is authenticated along with the value, so values can't be moved between cookies
+type cookieCodec struct {
+	aead cipher.AEAD
+}
+
+// newCookieCodec returns a codec encrypting with a key derived from secret. A random key is used if secret
+// is empty, so cookies don't outlive the process
+func newCookieCodec(secret string) (*cookieCodec, error) {
+	var key [sha256.Size]byte
+	if secret == "" {
+		if _, err := rand.Read(key[:]); err != nil {
+			return nil, err
+		}
+	} else {
+		key = sha256.Sum256([]byte(secret))
+	}
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &cookieCodec{aead: aead}, nil
+}
+
+// encode returns value marshalled to JSON and encrypted for the cookie name
+func (c *cookieCodec) encode(name string, value any) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+// decode decrypts the value of the cookie name into value
+func (c *cookieCodec) decode(name string, encoded string, value any) error {
+	ciphertext, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+	if len(ciphertext) < c.aead.NonceSize() {
+		return errors.New("cookie value too short")
+	}
+	nonce, ciphertext := ciphertext[:c.aead.NonceSize()], ciphertext[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plaintext, value)
+}
+
+// Session is the user logged in to the dashboard
+type Session struct {
+	Subject string `json:"sub"`
+	// Username is the value of the configured username claim, which defaults to the subject
+	Username string   `json:"user,omitempty"`
+	Email    string   `json:"email,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	// Expiry is when the login expires
+	Expiry time.Time `json:"exp"`
+}
+
+const sessionContextKey contextKey = "session"
+
+// GetSession retrieves the session of the logged in user from the request context, nil if login is disabled
+func GetSession(r *http.Request) *Session {
+	if session, ok := r.Context().Value(sessionContextKey).(*Session); ok {
+		return session
+	}
+	return nil
+}
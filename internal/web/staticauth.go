@@ -0,0 +1,256 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// credentialCheckInterval limits how often credential files are read to check for changes
+const credentialCheckInterval = 10 * time.Second
+
+const staticUserContextKey contextKey = "staticUser"
+
+// credentialFile holds the credentials parsed from a file, parsing it again when its contents change
+type credentialFile[T any] struct {
+	path     string
+	parse    func([]byte) (T, error)
+	interval time.Duration
+
+	mu       sync.Mutex
+	checked  time.Time
+	contents []byte
+	value    T
+}
+
+// newCredentialFile reads and parses the credentials in path
+func newCredentialFile[T any](path string, parse func([]byte) (T, error)) (*credentialFile[T], error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return &credentialFile[T]{
+		path:     path,
+		parse:    parse,
+		interval: credentialCheckInterval,
+		checked:  time.Now(),
+		contents: contents,
+		value:    value,
+	}, nil
+}
+
+// get returns the credentials, reading the file again if it wasn't checked for an interval. The last valid
+// credentials are kept while the file can't be read or parsed
+func (f *credentialFile[T]) get() T {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if time.Since(f.checked) < f.interval {
+		return f.value
+	}
+	f.checked = time.Now()
+
+	contents, err := os.ReadFile(f.path)
+	if err != nil {
+		logger.Warnf("Keeping credentials, unable to read %v: %v", f.path, err)
+		return f.value
+	}
+	if bytes.Equal(contents, f.contents) {
+		return f.value
+	}
+	value, err := f.parse(contents)
+	if err != nil {
+		logger.Warnf("Keeping credentials, unable to parse %v: %v", f.path, err)
+		return f.value
+	}
+	logger.Info("Reloaded credentials from ", f.path)
+	f.contents, f.value = contents, value
+	return f.value
+}
+
+// parseTokens parses bearer tokens, one per line. Only their hashes are kept, so tokens of any length are
+// compared in constant time
+func parseTokens(contents []byte) ([][sha256.Size]byte, error) {
+	var tokens [][sha256.Size]byte
+	for _, line := range strings.Split(string(contents), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			tokens = append(tokens, sha256.Sum256([]byte(line)))
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("no bearer tokens")
+	}
+	return tokens, nil
+}
+
+// parseHtpasswd parses users and their bcrypt hashed passwords in htpasswd format
+func parseHtpasswd(contents []byte) (map[string][]byte, error) {
+	users := map[string][]byte{}
+	for i, line := range strings.Split(string(contents), "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, hash, found := strings.Cut(line, ":")
+		if !found || user == "" {
+			return nil, fmt.Errorf("line %d is not user:hash", i+1)
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("password of user %q is not hashed with bcrypt, use htpasswd -B", user)
+		}
+		users[user] = []byte(hash)
+	}
+	if len(users) == 0 {
+		return nil, errors.New("no users")
+	}
+	return users, nil
+}
+
+// unknownUserHash is compared against the passwords of unknown users, so they take as long to reject as
+// wrong passwords and users can't be enumerated
+var unknownUserHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("unknown user"), bcrypt.DefaultCost)
+	return hash
+})
+
+// staticAuthenticator authenticates requests with bearer tokens or basic auth
+type staticAuthenticator struct {
+	scope  string
+	tokens *credentialFile[[][sha256.Size]byte]
+	users  *credentialFile[map[string][]byte]
+	// identity is the user of authenticated requests
+	identity user
+}
+
+// newStaticAuthenticator returns an authenticator for the credentials of cfg, nil if none are configured.
+// scope names the requests it authenticates in metrics
+func newStaticAuthenticator(scope string, cfg config.StaticAuth) (*staticAuthenticator, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+	authenticator := &staticAuthenticator{
+		scope:    scope,
+		identity: user{Name: cfg.GetUser(scope), Groups: cfg.Groups, Static: true},
+	}
+	var err error
+	if cfg.BearerTokenFile != "" {
+		if authenticator.tokens, err = newCredentialFile(cfg.BearerTokenFile, parseTokens); err != nil {
+			return nil, fmt.Errorf("invalid %v bearer tokens: %w", scope, err)
+		}
+	}
+	if cfg.HtpasswdFile != "" {
+		if authenticator.users, err = newCredentialFile(cfg.HtpasswdFile, parseHtpasswd); err != nil {
+			return nil, fmt.Errorf("invalid %v htpasswd: %w", scope, err)
+		}
+	}
+	return authenticator, nil
+}
+
+// authenticate checks the credentials of r. method is the scheme of the credentials, empty if r carries none
+func (a *staticAuthenticator) authenticate(r *http.Request) (method string, ok bool) {
+	scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	switch strings.ToLower(scheme) {
+	case "":
+		return "", false
+	case "bearer":
+		if a.tokens == nil {
+			return "bearer", false
+		}
+		sum := sha256.Sum256([]byte(strings.TrimSpace(credentials)))
+		match := 0
+		for _, token := range a.tokens.get() {
+			match |= subtle.ConstantTimeCompare(sum[:], token[:])
+		}
+		return "bearer", match == 1
+	case "basic":
+		username, password, found := r.BasicAuth()
+		if a.users == nil || !found {
+			return "basic", false
+		}
+		hash, known := a.users.get()[username]
+		if !known {
+			hash = unknownUserHash()
+		}
+		if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || !known {
+			return "basic", false
+		}
+		return "basic", true
+	default:
+		return "other", false
+	}
+}
+
+// challenge asks for the credentials the authenticator accepts
+func (a *staticAuthenticator) challenge(w http.ResponseWriter) {
+	if a.users != nil {
+		w.Header().Add("WWW-Authenticate", `Basic realm="forecastle", charset="UTF-8"`)
+	}
+	if a.tokens != nil {
+		w.Header().Add("WWW-Authenticate", `Bearer realm="forecastle"`)
+	}
+}
+
+// StaticAuthMiddleware requires requests to /api/* to carry the credentials of api, and other requests the
+// credentials of ui. Requests without credentials are passed on when login is true, so the OIDC middleware
+// can log users in, and rejected with 401 otherwise. Rejected requests are counted in the
+// forecastle_auth_failures_total metric. Must run after BasePathMiddleware and before OIDCMiddleware
+func StaticAuthMiddleware(api config.StaticAuth, ui config.StaticAuth, login bool) (func(http.Handler) http.Handler, error) {
+	apiAuthenticator, err := newStaticAuthenticator("api", api)
+	if err != nil {
+		return nil, err
+	}
+	uiAuthenticator, err := newStaticAuthenticator("ui", ui)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticator := uiAuthenticator
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				authenticator = apiAuthenticator
+			}
+			if authenticator == nil || slices.Contains(openPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			method, ok := authenticator.authenticate(r)
+			switch {
+			case ok:
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), staticUserContextKey, &authenticator.identity)))
+			case method == "" && login:
+				next.ServeHTTP(w, r)
+			default:
+				reason := "invalid"
+				if method == "" {
+					method, reason = "none", "missing"
+				}
+				authFailures.WithLabelValues(authenticator.scope, method, reason).Inc()
+				authenticator.challenge(w)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+			}
+		})
+	}, nil
+}
+
+// getStaticUser returns the identity of the static credentials of r, nil if there are none
+func getStaticUser(r *http.Request) *user {
+	user, _ := r.Context().Value(staticUserContextKey).(*user)
+	return user
+}
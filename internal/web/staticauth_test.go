@@ -0,0 +1,275 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// writeCredentials writes contents to a file in the test's temporary directory and returns its path
+func writeCredentials(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("Failed to write %v: %v", name, err)
+	}
+	return path
+}
+
+// htpasswdLine returns an htpasswd entry of user with a bcrypt hashed password
+func htpasswdLine(t *testing.T, user string, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	return user + ":" + string(hash) + "\n"
+}
+
+// newStaticAuthTestHandler returns the static auth middleware wrapping a handler that echoes the static user
+func newStaticAuthTestHandler(t *testing.T, api config.StaticAuth, ui config.StaticAuth, login bool) http.Handler {
+	t.Helper()
+	middleware, err := StaticAuthMiddleware(api, ui, login)
+	if err != nil {
+		t.Fatalf("StaticAuthMiddleware() error = %v", err)
+	}
+	return middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if staticUser := getStaticUser(r); staticUser != nil {
+			_, _ = w.Write([]byte(staticUser.Name))
+		}
+	}))
+}
+
+func serveStaticAuth(handler http.Handler, path string, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStaticAuthMiddleware_BearerToken(t *testing.T) {
+	tokens := writeCredentials(t, "tokens", "# automation\nsecret-1\n\n  secret-2  \n")
+	handler := newStaticAuthTestHandler(t, config.StaticAuth{BearerTokenFile: tokens}, config.StaticAuth{}, false)
+
+	for _, token := range []string{"secret-1", "secret-2"} {
+		rec := serveStaticAuth(handler, "/api/apps", "Bearer "+token)
+		if rec.Code != http.StatusOK || rec.Body.String() != "forecastle:api" {
+			t.Errorf("Expected token %q to be accepted, got %d %q", token, rec.Code, rec.Body.String())
+		}
+	}
+
+	invalid := authFailures.WithLabelValues("api", "bearer", "invalid")
+	before := testutil.ToFloat64(invalid)
+	for _, authorization := range []string{"Bearer secret", "Bearer # automation", "Bearer "} {
+		rec := serveStaticAuth(handler, "/api/apps", authorization)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected %q to be rejected, got %d", authorization, rec.Code)
+		}
+		if got := rec.Header().Get("WWW-Authenticate"); got != `Bearer realm="forecastle"` {
+			t.Errorf("Expected a bearer challenge, got %q", got)
+		}
+	}
+	if got := testutil.ToFloat64(invalid) - before; got != 3 {
+		t.Errorf("Expected 3 invalid bearer failures to be counted, got %v", got)
+	}
+
+	basic := authFailures.WithLabelValues("api", "basic", "invalid")
+	before = testutil.ToFloat64(basic)
+	if rec := serveStaticAuth(handler, "/api/apps", "Basic dXNlcjpzZWNyZXQtMQ=="); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected basic auth to be rejected without an htpasswd file, got %d", rec.Code)
+	}
+	if got := testutil.ToFloat64(basic) - before; got != 1 {
+		t.Errorf("Expected the basic failure to be counted, got %v", got)
+	}
+}
+
+func TestStaticAuthMiddleware_TokenRotation(t *testing.T) {
+	tokens := writeCredentials(t, "tokens", "old-token\n")
+	authenticator, err := newStaticAuthenticator("api", config.StaticAuth{BearerTokenFile: tokens})
+	if err != nil {
+		t.Fatalf("newStaticAuthenticator() error = %v", err)
+	}
+	authenticator.tokens.interval = 0
+	authenticate := func(token string) bool {
+		req := httptest.NewRequest(http.MethodGet, "/api/apps", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		_, ok := authenticator.authenticate(req)
+		return ok
+	}
+
+	if !authenticate("old-token") {
+		t.Fatal("Expected the initial token to be accepted")
+	}
+	if err := os.WriteFile(tokens, []byte("new-token\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rotate tokens: %v", err)
+	}
+	if authenticate("old-token") || !authenticate("new-token") {
+		t.Error("Expected the rotated token to replace the old one")
+	}
+
+	// Invalid or missing files keep the last valid tokens, so a botched rotation doesn't lock everyone out
+	if err := os.WriteFile(tokens, []byte("# no tokens\n"), 0o600); err != nil {
+		t.Fatalf("Failed to empty tokens: %v", err)
+	}
+	if !authenticate("new-token") {
+		t.Error("Expected the last valid tokens to be kept when the file has none")
+	}
+	if err := os.Remove(tokens); err != nil {
+		t.Fatalf("Failed to remove tokens: %v", err)
+	}
+	if !authenticate("new-token") {
+		t.Error("Expected the last valid tokens to be kept when the file is removed")
+	}
+}
+
+func TestStaticAuthMiddleware_Htpasswd(t *testing.T) {
+	htpasswd := writeCredentials(t, "htpasswd", htpasswdLine(t, "jane", "hunter2")+htpasswdLine(t, "joe", "correct horse"))
+	handler := newStaticAuthTestHandler(t, config.StaticAuth{}, config.StaticAuth{HtpasswdFile: htpasswd}, false)
+
+	basicAuth := func(user string, password string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(user, password)
+		return req.Header.Get("Authorization")
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      int
+		wantUser      string
+	}{
+		{name: "ValidUser", authorization: basicAuth("jane", "hunter2"), wantCode: http.StatusOK, wantUser: "forecastle:ui"},
+		{name: "PasswordWithSpaces", authorization: basicAuth("joe", "correct horse"), wantCode: http.StatusOK, wantUser: "forecastle:ui"},
+		{name: "WrongPassword", authorization: basicAuth("jane", "hunter3"), wantCode: http.StatusUnauthorized},
+		{name: "PasswordOfOtherUser", authorization: basicAuth("jane", "correct horse"), wantCode: http.StatusUnauthorized},
+		{name: "UnknownUser", authorization: basicAuth("mallory", "hunter2"), wantCode: http.StatusUnauthorized},
+		{name: "MalformedCredentials", authorization: "Basic not-base64", wantCode: http.StatusUnauthorized},
+		{name: "OtherScheme", authorization: "Digest username=jane", wantCode: http.StatusUnauthorized},
+		{name: "Missing", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveStaticAuth(handler, "/", tt.authorization)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+			if tt.wantCode == http.StatusOK && rec.Body.String() != tt.wantUser {
+				t.Errorf("Expected user %q, got %q", tt.wantUser, rec.Body.String())
+			}
+			if tt.wantCode == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a challenge for rejected requests")
+			}
+		})
+	}
+
+	missing := authFailures.WithLabelValues("ui", "none", "missing")
+	before := testutil.ToFloat64(missing)
+	serveStaticAuth(handler, "/", "")
+	if got := testutil.ToFloat64(missing) - before; got != 1 {
+		t.Errorf("Expected the missing credentials to be counted, got %v", got)
+	}
+}
+
+func TestStaticAuthMiddleware_Scopes(t *testing.T) {
+	tokens := writeCredentials(t, "tokens", "api-token\n")
+	htpasswd := writeCredentials(t, "htpasswd", htpasswdLine(t, "jane", "hunter2"))
+	handler := newStaticAuthTestHandler(t, config.StaticAuth{BearerTokenFile: tokens}, config.StaticAuth{HtpasswdFile: htpasswd}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("jane", "hunter2")
+	basic := req.Header.Get("Authorization")
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		want          int
+	}{
+		{name: "TokenForAPI", path: "/api/apps", authorization: "Bearer api-token", want: http.StatusOK},
+		{name: "TokenForUI", path: "/", authorization: "Bearer api-token", want: http.StatusUnauthorized},
+		{name: "BasicForUI", path: "/assets/app.js", authorization: basic, want: http.StatusOK},
+		{name: "BasicForAPI", path: "/api/apps", authorization: basic, want: http.StatusUnauthorized},
+		{name: "HealthzOpen", path: "/healthz", want: http.StatusOK},
+		{name: "ReadyzOpen", path: "/readyz", want: http.StatusOK},
+		{name: "OtherPathsProtected", path: "/metrics", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serveStaticAuth(handler, tt.path, tt.authorization); rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+
+	t.Run("UnprotectedScope", func(t *testing.T) {
+		handler := newStaticAuthTestHandler(t, config.StaticAuth{BearerTokenFile: tokens}, config.StaticAuth{}, false)
+		if rec := serveStaticAuth(handler, "/", ""); rec.Code != http.StatusOK {
+			t.Errorf("Expected the dashboard to stay open without ui credentials, got %d", rec.Code)
+		}
+	})
+}
+
+func TestStaticAuthMiddleware_Login(t *testing.T) {
+	tokens := writeCredentials(t, "tokens", "api-token\n")
+	handler := newStaticAuthTestHandler(t, config.StaticAuth{BearerTokenFile: tokens}, config.StaticAuth{}, true)
+
+	// Requests without credentials are left to the OIDC middleware, invalid credentials are still rejected
+	if rec := serveStaticAuth(handler, "/api/apps", ""); rec.Code != http.StatusOK || rec.Body.String() != "" {
+		t.Errorf("Expected requests without credentials to be passed on anonymously, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serveStaticAuth(handler, "/api/apps", "Bearer wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected invalid tokens to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestStaticAuthMiddleware_OIDCAcceptsStaticUser(t *testing.T) {
+	idp := newMockIdP(t)
+	tokens := writeCredentials(t, "tokens", "api-token\n")
+	staticAuth, err := StaticAuthMiddleware(config.StaticAuth{BearerTokenFile: tokens}, config.StaticAuth{}, true)
+	if err != nil {
+		t.Fatalf("StaticAuthMiddleware() error = %v", err)
+	}
+	t.Setenv(config.OIDCClientSecretEnv, "client-secret")
+	t.Setenv(config.OIDCCookieSecretEnv, "cookie-secret")
+	oidc, err := OIDCMiddleware(config.OIDC{Enabled: true, IssuerURL: idp.URL, ClientID: "forecastle"}, nil)
+	if err != nil {
+		t.Fatalf("OIDCMiddleware() error = %v", err)
+	}
+	handler := staticAuth(oidc(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if staticUser := getStaticUser(r); staticUser != nil {
+			_, _ = w.Write([]byte(staticUser.Name))
+		}
+	})))
+
+	if rec := serveStaticAuth(handler, "/api/apps", "Bearer api-token"); rec.Code != http.StatusOK || rec.Body.String() != "forecastle:api" {
+		t.Errorf("Expected token requests to bypass login, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serveStaticAuth(handler, "/api/apps", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous API requests to require login, got %d", rec.Code)
+	}
+}
+
+func TestParseHtpasswd(t *testing.T) {
+	if _, err := parseHtpasswd([]byte("jane:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n")); err == nil {
+		t.Error("Expected non bcrypt hashes to be rejected")
+	}
+	if _, err := parseHtpasswd([]byte("jane\n")); err == nil {
+		t.Error("Expected lines without a hash to be rejected")
+	}
+	if _, err := parseHtpasswd([]byte("# no users\n")); err == nil {
+		t.Error("Expected files without users to be rejected")
+	}
+	users, err := parseHtpasswd([]byte("# admins\n" + htpasswdLine(t, "jane", "hunter2")))
+	if err != nil || len(users) != 1 {
+		t.Errorf("parseHtpasswd() = %v, %v, want jane", users, err)
+	}
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/kube/rbac"
+)
+
+// user is the identified user of a request
+type user struct {
+	Name   string
+	Groups []string
+	// Static is set for the identities of static credentials, which are reviewed without the RBAC prefixes
+	// of OIDC users
+	Static bool
+}
+
+// requestUser returns the user of r, identified by the OIDC session, static credentials or the headers of
+// a trusted authenticating proxy. Returns nil for anonymous requests
+func requestUser(r *http.Request, cfg *config.Config) *user {
+	if session := GetSession(r); session != nil {
+		name := session.Username
+		if name == "" {
+			name = session.Subject
+		}
+		return &user{Name: name, Groups: session.Groups}
+	}
+	if staticUser := getStaticUser(r); staticUser != nil {
+		return staticUser
+	}
+	if cfg == nil || !cfg.Auth.Proxy.Enabled {
+		return nil
+	}
+
+	trustedProxies, err := cfg.Auth.Proxy.Parse()
+	if err != nil {
+		logger.Warn("Ignoring invalid auth proxy: ", err)
+		return nil
+	}
+	if addr, ok := parseAddr(r.RemoteAddr); !ok || !containsAddr(trustedProxies, addr) {
+		return nil
+	}
+	name := strings.TrimSpace(r.Header.Get(cfg.Auth.Proxy.GetUserHeader()))
+	if name == "" {
+		return nil
+	}
+
+	var groups []string
+	for _, header := range r.Header.Values(cfg.Auth.Proxy.GetGroupsHeader()) {
+		for _, group := range strings.Split(header, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return &user{Name: name, Groups: groups}
+}
+
+// appsForUser returns the apps the user of r may see. Apps with allowed groups are only visible to users
+// in one of them. When authorizer is set, apps discovered from a namespace are only visible to users with
+// access to it. Restricted apps are never visible to anonymous users
+func appsForUser(r *http.Request, apps []forecastle.App, cfg *config.Config, authorizer *rbac.Authorizer) []forecastle.App {
+	user := requestUser(r, cfg)
+	var groups []string
+	if user != nil {
+		groups = user.Groups
+	}
+
+	// namespaces holds the reviews of this request, so failed reviews aren't repeated for every app
+	namespaces := map[string]bool{}
+	allowed := func(namespace string) bool {
+		if user == nil {
+			return false
+		}
+		if result, ok := namespaces[namespace]; ok {
+			return result
+		}
+		if user.Static {
+			namespaces[namespace] = authorizer.AllowedExact(r.Context(), user.Name, user.Groups, namespace)
+		} else {
+			namespaces[namespace] = authorizer.Allowed(r.Context(), user.Name, user.Groups, namespace)
+		}
+		return namespaces[namespace]
+	}
+
+	var visible []forecastle.App
+	for _, app := range apps {
+		if !app.IsVisibleTo(groups) {
+			continue
+		}
+		if authorizer != nil && app.Origin != nil && app.Origin.Namespace != "" && !allowed(app.Origin.Namespace) {
+			continue
+		}
+		visible = append(visible, app)
+	}
+	return visible
+}
@@ -0,0 +1,301 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	forecastlefake "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/fake"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/kube/rbac"
+	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestHandler_AppsHandler_AllowedGroups(t *testing.T) {
+	clients := &kube.Clients{
+		KubernetesClient:     fake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+		ForecastleAppsClient: forecastlefake.NewSimpleClientset(),
+	}
+	cfg := &config.Config{
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		CustomApps: []config.CustomApp{
+			{Name: "admin-console", Group: "apps", URL: "https://admin.example.com", AllowedGroups: []string{"admins"}},
+			{Name: "grafana", Group: "apps", URL: "https://grafana.example.com"},
+			{Name: "vault", Group: "apps", URL: "https://vault.example.com", AllowedGroups: []string{"admins", "platform"}},
+		},
+		Auth: config.Auth{Proxy: config.AuthProxy{Enabled: true, TrustedProxies: []string{"10.0.0.0/8"}}},
+	}
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	handler.refreshCache(context.Background())
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		session    *Session
+		want       []string
+	}{
+		{
+			name:       "Anonymous",
+			remoteAddr: "10.0.0.5:8080",
+			want:       []string{"grafana"},
+		},
+		{
+			name:       "ProxyUserInGroup",
+			remoteAddr: "10.0.0.5:8080",
+			headers:    map[string]string{"X-Forwarded-User": "jane", "X-Forwarded-Groups": "devs, platform"},
+			want:       []string{"grafana", "vault"},
+		},
+		{
+			name:       "ProxyUserWithoutGroups",
+			remoteAddr: "10.0.0.5:8080",
+			headers:    map[string]string{"X-Forwarded-User": "jane"},
+			want:       []string{"grafana"},
+		},
+		{
+			name:       "GroupsWithoutUser",
+			remoteAddr: "10.0.0.5:8080",
+			headers:    map[string]string{"X-Forwarded-Groups": "admins"},
+			want:       []string{"grafana"},
+		},
+		{
+			name:       "UntrustedProxyHeadersIgnored",
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"X-Forwarded-User": "mallory", "X-Forwarded-Groups": "admins"},
+			want:       []string{"grafana"},
+		},
+		{
+			name:       "OIDCSession",
+			remoteAddr: "203.0.113.7:51234",
+			session:    &Session{Subject: "joe", Groups: []string{"admins"}},
+			want:       []string{"admin-console", "grafana", "vault"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/apps", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			if tt.session != nil {
+				req = req.WithContext(context.WithValue(req.Context(), sessionContextKey, tt.session))
+			}
+			rec := httptest.NewRecorder()
+			handler.AppsHandler(rec, req)
+
+			var apps []forecastle.App
+			if err := json.NewDecoder(rec.Body).Decode(&apps); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var names []string
+			for _, app := range apps {
+				names = append(names, app.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Expected apps %v, got %v", tt.want, names)
+			}
+		})
+	}
+
+	t.Run("AppHandlerHidesDisallowedApp", func(t *testing.T) {
+		handler.appsCacheMu.RLock()
+		var id string
+		for _, app := range handler.appsCache {
+			if app.Name == "admin-console" {
+				id = app.ID
+			}
+		}
+		handler.appsCacheMu.RUnlock()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/apps/"+id, nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		handler.AppHandler(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected app outside the user's groups to be not found, got %d", rec.Code)
+		}
+	})
+}
+
+func TestAppsForUser_RBAC(t *testing.T) {
+	client := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	reviews := 0
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviews++
+		review.Status.Allowed = review.Spec.User == "jane" && review.Spec.ResourceAttributes.Namespace == "team-a"
+		return true, review, nil
+	})
+	authorizer := rbac.NewAuthorizer(client, config.RBAC{Enabled: true})
+
+	apps := []forecastle.App{
+		{Name: "a1", Origin: &forecastle.Origin{Kind: "Ingress", Namespace: "team-a", Name: "a1"}},
+		{Name: "a2", Origin: &forecastle.Origin{Kind: "ForecastleApp", Namespace: "team-a", Name: "a2"}},
+		{Name: "b", Origin: &forecastle.Origin{Kind: "Ingress", Namespace: "team-b", Name: "b"}},
+		{Name: "custom", Origin: &forecastle.Origin{Kind: "CustomApp", Name: "custom"}},
+	}
+	names := func(apps []forecastle.App) (names []string) {
+		for _, app := range apps {
+			names = append(names, app.Name)
+		}
+		return names
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/apps", nil)
+	if got := names(appsForUser(req, apps, &config.Config{}, authorizer)); !reflect.DeepEqual(got, []string{"custom"}) {
+		t.Errorf("Expected anonymous users to only see apps outside namespaces, got %v", got)
+	}
+	if reviews != 0 {
+		t.Errorf("Expected anonymous users not to be reviewed, got %d reviews", reviews)
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), sessionContextKey, &Session{Subject: "id-1", Username: "jane"}))
+	if got := names(appsForUser(req, apps, &config.Config{}, authorizer)); !reflect.DeepEqual(got, []string{"a1", "a2", "custom"}) {
+		t.Errorf("Expected jane to see the apps of team-a, got %v", got)
+	}
+	if reviews != 2 {
+		t.Errorf("Expected a review per namespace, got %d reviews", reviews)
+	}
+}
+
+func TestAppsForUser_RBACStaticCredentials(t *testing.T) {
+	client := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	var reviewed []authorizationv1.SubjectAccessReviewSpec
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviewed = append(reviewed, review.Spec)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "team-a"
+		return true, review, nil
+	})
+	authorizer := rbac.NewAuthorizer(client, config.RBAC{Enabled: true, UsernamePrefix: "oidc:", GroupsPrefix: "oidc:"})
+
+	htpasswd := writeCredentials(t, "htpasswd", htpasswdLine(t, "jane", "hunter2"))
+	staticAuth, err := StaticAuthMiddleware(config.StaticAuth{}, config.StaticAuth{
+		HtpasswdFile: htpasswd, User: "dashboard", Groups: []string{"viewers"}}, false)
+	if err != nil {
+		t.Fatalf("StaticAuthMiddleware() error = %v", err)
+	}
+	apps := []forecastle.App{
+		{Name: "a", Origin: &forecastle.Origin{Kind: "Ingress", Namespace: "team-a", Name: "a"}},
+		{Name: "b", Origin: &forecastle.Origin{Kind: "Ingress", Namespace: "team-b", Name: "b"}},
+	}
+	var got []forecastle.App
+	handler := staticAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = appsForUser(r, apps, &config.Config{}, authorizer)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("jane", "hunter2")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(got) != 1 || got[0].Name != "a" {
+		t.Errorf("Expected the apps of team-a, got %v", got)
+	}
+	if len(reviewed) != 2 {
+		t.Fatalf("Expected a review per namespace, got %d reviews", len(reviewed))
+	}
+	for _, review := range reviewed {
+		if review.User != "dashboard" || !reflect.DeepEqual(review.Groups, []string{"viewers"}) {
+			t.Errorf("Expected the configured identity to be reviewed without prefixes, got %v %v", review.User, review.Groups)
+		}
+	}
+}
+
+func TestHandler_ConfigHandler_HidesCustomApps(t *testing.T) {
+	clients := &kube.Clients{
+		KubernetesClient:     fake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+		ForecastleAppsClient: forecastlefake.NewSimpleClientset(),
+	}
+	cfg := &config.Config{
+		Title:             "Forecastle",
+		NamespaceSelector: config.NamespaceSelector{Any: true},
+		CustomApps: []config.CustomApp{
+			{Name: "admin-console", Group: "apps", URL: "https://admin.example.com", AllowedGroups: []string{"admins"}},
+			{Name: "vpn-only", Group: "apps", URL: "https://vpn-only.example.com", NetworkRestricted: true},
+		},
+		ClientNetworks: config.ClientNetworks{Internal: []string{"192.168.0.0/16"}},
+	}
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	handler.refreshCache(context.Background())
+
+	// An anonymous client outside the internal networks may see neither app
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	rec := httptest.NewRecorder()
+	handler.ConfigHandler(rec, req)
+
+	body := rec.Body.String()
+	var response map[string]any
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["title"] != "Forecastle" {
+		t.Errorf("Expected the title to be returned, got %v", response["title"])
+	}
+	if _, ok := response["customApps"]; ok {
+		t.Error("Expected custom apps not to be returned by /api/config")
+	}
+	for _, url := range []string{"admin.example.com", "vpn-only.example.com"} {
+		if strings.Contains(body, url) {
+			t.Errorf("Expected %v not to be disclosed, got %v", url, body)
+		}
+	}
+}
+
+func TestHandler_AppHandler_HidesAllowedGroups(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	ingress := testutil.CreateIngressWithHost("admin-console", "admin.example.com")
+	ingress.Namespace = "default"
+	ingress.Annotations = map[string]string{
+		annotations.ForecastleExposeAnnotation:        "true",
+		annotations.ForecastleAllowedGroupsAnnotation: "admins",
+	}
+	_, _ = kubeClient.NetworkingV1().Ingresses("default").Create(context.Background(), ingress, metav1.CreateOptions{})
+
+	clients := &kube.Clients{KubernetesClient: kubeClient, ForecastleAppsClient: forecastlefake.NewSimpleClientset()}
+	cfg := &config.Config{NamespaceSelector: config.NamespaceSelector{Any: true}}
+	handler := NewHandler(clients, func() (*config.Config, error) { return cfg, nil }, time.Minute)
+	handler.refreshCache(context.Background())
+
+	id := forecastle.NewID("", forecastle.Ingress, &forecastle.Origin{Kind: "Ingress", Namespace: "default", Name: "admin-console"})
+	req := httptest.NewRequest(http.MethodGet, "/api/apps/"+id, nil)
+	req.SetPathValue("id", id)
+	req = req.WithContext(context.WithValue(req.Context(), sessionContextKey, &Session{Subject: "joe", Groups: []string{"admins"}}))
+	rec := httptest.NewRecorder()
+	handler.AppHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var response AppDetailResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if _, ok := response.Annotations[annotations.ForecastleAllowedGroupsAnnotation]; ok {
+		t.Errorf("Expected the allowed groups not to be disclosed, got %v", response.Annotations)
+	}
+	if response.Annotations[annotations.ForecastleExposeAnnotation] != "true" {
+		t.Errorf("Expected the other forecastle annotations to be returned, got %v", response.Annotations)
+	}
+
+	// The cached app keeps its annotations
+	handler.appsCacheMu.RLock()
+	defer handler.appsCacheMu.RUnlock()
+	if handler.appsCache[0].Annotations[annotations.ForecastleAllowedGroupsAnnotation] != "admins" {
+		t.Error("Expected the cached annotations not to be modified")
+	}
+}
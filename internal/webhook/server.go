@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+)
+
+// ServerConfig holds configuration for the admission webhook server
+type ServerConfig struct {
+	Port     int
+	CertFile string
+	KeyFile  string
+	// Config sets the annotation scheme objects are validated in
+	Config config.Config
+	// WarnOnly admits invalid objects with warnings instead of rejecting them
+	WarnOnly bool
+}
+
+// RunServer serves the validating admission webhook over TLS on /validate until ctx is done
+func RunServer(ctx context.Context, cfg ServerConfig) error {
+	mux := http.NewServeMux()
+	mux.Handle("POST /validate", NewHandler(cfg.Config, cfg.WarnOnly))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      mux,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("Shutting down webhook server...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Error during webhook server shutdown: ", err)
+		}
+	}()
+
+	logger.Info("Starting admission webhook server on port ", cfg.Port)
+	return server.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+}
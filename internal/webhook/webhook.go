@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/kube/wrappers"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var logger = log.New()
+
+// Handler validates ForecastleApps, and the forecastle annotations of Ingresses and HTTPRoutes, sent to it
+// as AdmissionReview requests by a ValidatingWebhookConfiguration
+type Handler struct {
+	// scheme translates annotations into forecastle annotations before they are validated, as the dashboard
+	// does before reading them
+	scheme *annotations.Scheme
+	// warnOnly admits invalid objects, returning their problems as warnings
+	warnOnly bool
+}
+
+// NewHandler creates a Handler validating annotations in the annotation scheme of appConfig. With warnOnly,
+// invalid objects are admitted with warnings instead of rejected
+func NewHandler(appConfig config.Config, warnOnly bool) *Handler {
+	return &Handler{scheme: appConfig.GetAnnotationScheme(), warnOnly: warnOnly}
+}
+
+// ServeHTTP answers an AdmissionReview request
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, "invalid AdmissionReview: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "AdmissionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	response := h.review(review.Request)
+	response.UID = review.Request.UID
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Response: response,
+	}); err != nil {
+		logger.Error("Failed to encode AdmissionReview response: ", err)
+	}
+}
+
+// review validates the object of request
+func (h *Handler) review(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Operation == admissionv1.Delete {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	var errs, warnings []string
+	var err error
+	switch request.Kind.Group + "/" + request.Kind.Kind {
+	case v1beta1.SchemeGroupVersion.Group + "/ForecastleApp":
+		var app v1beta1.ForecastleApp
+		if err = json.Unmarshal(request.Object.Raw, &app); err == nil {
+			errs, warnings = ValidateForecastleApp(&app)
+		}
+	case "networking.k8s.io/Ingress", "gateway.networking.k8s.io/HTTPRoute":
+		var object metav1.PartialObjectMetadata
+		if err = json.Unmarshal(request.Object.Raw, &object); err == nil {
+			errs, warnings = wrappers.ValidateAnnotations(h.scheme.Normalize(object.Annotations))
+		}
+	default:
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+	if err != nil {
+		return denied(fmt.Sprintf("unable to decode %v: %v", request.Kind.Kind, err))
+	}
+
+	if len(errs) == 0 {
+		return &admissionv1.AdmissionResponse{Allowed: true, Warnings: warnings}
+	}
+	logger.Infof("Invalid %v '%v/%v': %v", request.Kind.Kind, request.Namespace, request.Name, strings.Join(errs, "; "))
+	if h.warnOnly {
+		return &admissionv1.AdmissionResponse{Allowed: true, Warnings: append(errs, warnings...)}
+	}
+	response := denied(fmt.Sprintf("invalid %v: %v", request.Kind.Kind, strings.Join(errs, "; ")))
+	response.Warnings = warnings
+	return response
+}
+
+func denied(message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: message,
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
+
+// ValidateForecastleApp checks that forecastle can show app. It returns errors for specs forecastle can't use,
+// and warnings for fields that are ignored or likely mistakes
+func ValidateForecastleApp(app *v1beta1.ForecastleApp) (errs []string, warnings []string) {
+	spec := app.Spec
+	if spec.Name == "" {
+		errs = append(errs, "spec.name is required")
+	}
+	if spec.Icon != "" {
+		if _, err := wrappers.ParseURL(spec.Icon); err != nil {
+			warnings = append(warnings, "spec.icon: "+err.Error())
+		}
+	}
+
+	switch {
+	case spec.URL != "":
+		if _, err := wrappers.ParseURL(spec.URL); err != nil {
+			errs = append(errs, "spec.url: "+err.Error())
+		}
+		if spec.URLFrom != nil {
+			warnings = append(warnings, "spec.urlFrom is ignored because spec.url is set")
+		}
+	case spec.URLFrom != nil:
+		errs = append(errs, validateURLSource(spec.URLFrom, &warnings)...)
+	default:
+		errs = append(errs, "one of spec.url or spec.urlFrom is required")
+	}
+
+	for i, link := range spec.Links {
+		if _, linkErrs := wrappers.ValidLinks([]forecastle.Link{{Label: link.Label, URL: link.URL}}); len(linkErrs) > 0 {
+			errs = append(errs, fmt.Sprintf("spec.links[%d]: %v", i, linkErrs[0]))
+		}
+	}
+
+	keys := make([]string, 0, len(spec.PropertyTypes))
+	for key := range spec.PropertyTypes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := spec.Properties[key]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("spec.propertyTypes[%v] is ignored because there is no such property", key))
+			continue
+		}
+		if err := validateProperty(spec.PropertyTypes[key], value); err != nil {
+			errs = append(errs, fmt.Sprintf("spec.properties[%v]: %v", key, err))
+		}
+	}
+	return errs, warnings
+}
+
+// validateURLSource checks that urlFrom references exactly one object, in the order discovery looks at them
+func validateURLSource(urlFrom *v1beta1.URLSource, warnings *[]string) (errs []string) {
+	type ref struct {
+		field string
+		name  string
+	}
+	var refs []ref
+	if urlFrom.IngressRef != nil {
+		refs = append(refs, ref{"ingressRef", urlFrom.IngressRef.Name})
+	}
+	if urlFrom.RouteRef != nil {
+		refs = append(refs, ref{"routeRef", urlFrom.RouteRef.Name})
+	}
+	if urlFrom.IngressRouteRef != nil {
+		refs = append(refs, ref{"ingressRouteRef", urlFrom.IngressRouteRef.Name})
+	}
+	if urlFrom.HTTPRouteRef != nil {
+		refs = append(refs, ref{"httpRouteRef", urlFrom.HTTPRouteRef.Name})
+	}
+	if urlFrom.ServiceRef != nil {
+		refs = append(refs, ref{"serviceRef", urlFrom.ServiceRef.Name})
+	}
+
+	if len(refs) == 0 {
+		return []string{"spec.urlFrom must set one of ingressRef, routeRef, ingressRouteRef, httpRouteRef or serviceRef"}
+	}
+	if len(refs) > 1 {
+		*warnings = append(*warnings, fmt.Sprintf("spec.urlFrom sets %v references, only %v is used", len(refs), refs[0].field))
+	}
+	for _, r := range refs {
+		if r.name == "" {
+			errs = append(errs, fmt.Sprintf("spec.urlFrom.%v.name is required", r.field))
+		}
+	}
+	return errs
+}
+
+// validateProperty checks that value is a valid value of propertyType
+func validateProperty(propertyType v1beta1.PropertyType, value string) error {
+	var err error
+	switch propertyType {
+	case v1beta1.PropertyTypeText, "":
+	case v1beta1.PropertyTypeURL:
+		_, err = wrappers.ParseURL(value)
+	case v1beta1.PropertyTypeNumber:
+		if _, parseErr := strconv.ParseFloat(value, 64); parseErr != nil {
+			err = fmt.Errorf("%q is not a number", value)
+		}
+	case v1beta1.PropertyTypeBoolean:
+		if _, parseErr := strconv.ParseBool(value); parseErr != nil {
+			err = fmt.Errorf("%q is not a boolean", value)
+		}
+	default:
+		err = fmt.Errorf("unknown property type %q", propertyType)
+	}
+	return err
+}
@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func sendAdmissionReview(t *testing.T, handler http.Handler, kind metav1.GroupVersionKind, operation admissionv1.Operation, object interface{}) *admissionv1.AdmissionResponse {
+	t.Helper()
+	raw, err := json.Marshal(object)
+	if err != nil {
+		t.Fatalf("Marshalling object failed: %v", err)
+	}
+	body, err := json.Marshal(admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       types.UID("review-uid"),
+			Kind:      kind,
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Marshalling AdmissionReview failed: %v", err)
+	}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Sending AdmissionReview failed: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
+		t.Fatalf("Decoding AdmissionReview failed: %v", err)
+	}
+	if review.Response == nil || review.Response.UID != "review-uid" {
+		t.Fatalf("Expected a response for review-uid, got %+v", review.Response)
+	}
+	return review.Response
+}
+
+var (
+	forecastleAppKind = metav1.GroupVersionKind{Group: "forecastle.stakater.com", Version: "v1beta1", Kind: "ForecastleApp"}
+	ingressKind       = metav1.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+)
+
+func TestHandler_ForecastleApp(t *testing.T) {
+	tests := []struct {
+		name         string
+		app          *v1beta1.ForecastleApp
+		warnOnly     bool
+		wantAllowed  bool
+		wantMessage  string
+		wantWarnings []string
+	}{
+		{
+			name:        "ValidURL",
+			app:         testutil.CreateForecastleApp("app", "https://app.example.com", "default", "https://icon"),
+			wantAllowed: true,
+		},
+		{
+			name:        "ValidURLFrom",
+			app:         testutil.CreateForecastleAppWithURLFromIngress("app", "default", "https://icon", "app-ingress"),
+			wantAllowed: true,
+		},
+		{
+			name:        "MissingURL",
+			app:         &v1beta1.ForecastleApp{Spec: v1beta1.ForecastleAppSpec{Name: "app"}},
+			wantMessage: "invalid ForecastleApp: one of spec.url or spec.urlFrom is required",
+		},
+		{
+			name:        "URLWithoutScheme",
+			app:         testutil.CreateForecastleApp("app", "app.example.com", "default", "https://icon"),
+			wantMessage: `invalid ForecastleApp: spec.url: URL "app.example.com" is missing a scheme`,
+		},
+		{
+			name:         "URLWithoutSchemeWarnOnly",
+			app:          testutil.CreateForecastleApp("app", "app.example.com", "default", "https://icon"),
+			warnOnly:     true,
+			wantAllowed:  true,
+			wantWarnings: []string{`spec.url: URL "app.example.com" is missing a scheme`},
+		},
+		{
+			name: "EmptyURLFrom",
+			app: &v1beta1.ForecastleApp{Spec: v1beta1.ForecastleAppSpec{
+				Name:    "app",
+				URLFrom: &v1beta1.URLSource{},
+			}},
+			wantMessage: "invalid ForecastleApp: spec.urlFrom must set one of ingressRef, routeRef, ingressRouteRef, httpRouteRef or serviceRef",
+		},
+		{
+			name: "SeveralRefs",
+			app: &v1beta1.ForecastleApp{Spec: v1beta1.ForecastleAppSpec{
+				Name: "app",
+				URLFrom: &v1beta1.URLSource{
+					IngressRef:   &v1beta1.IngressURLSource{ObjectReference: v1beta1.ObjectReference{Name: "app"}},
+					HTTPRouteRef: &v1beta1.HTTPRouteURLSource{ObjectReference: v1beta1.ObjectReference{Name: "app"}},
+				},
+			}},
+			wantAllowed:  true,
+			wantWarnings: []string{"spec.urlFrom sets 2 references, only ingressRef is used"},
+		},
+		{
+			name: "InvalidLink",
+			app: &v1beta1.ForecastleApp{Spec: v1beta1.ForecastleAppSpec{
+				Name:  "app",
+				URL:   "https://app.example.com",
+				Links: []v1beta1.Link{{Label: "Docs", URL: "docs.example.com"}},
+			}},
+			wantMessage: `invalid ForecastleApp: spec.links[0]: link "Docs": URL "docs.example.com" is missing a scheme`,
+		},
+		{
+			name: "InvalidTypedProperty",
+			app: &v1beta1.ForecastleApp{Spec: v1beta1.ForecastleAppSpec{
+				Name:          "app",
+				URL:           "https://app.example.com",
+				Properties:    map[string]string{"Replicas": "three"},
+				PropertyTypes: map[string]v1beta1.PropertyType{"Replicas": v1beta1.PropertyTypeNumber},
+			}},
+			wantMessage: `invalid ForecastleApp: spec.properties[Replicas]: "three" is not a number`,
+		},
+		{
+			name: "TypeOfMissingProperty",
+			app: &v1beta1.ForecastleApp{Spec: v1beta1.ForecastleAppSpec{
+				Name:          "app",
+				URL:           "https://app.example.com",
+				PropertyTypes: map[string]v1beta1.PropertyType{"Docs": v1beta1.PropertyTypeURL},
+			}},
+			wantAllowed:  true,
+			wantWarnings: []string{"spec.propertyTypes[Docs] is ignored because there is no such property"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := sendAdmissionReview(t, NewHandler(config.Config{}, tt.warnOnly), forecastleAppKind, admissionv1.Create, tt.app)
+			if response.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v (result %+v)", response.Allowed, tt.wantAllowed, response.Result)
+			}
+			if !tt.wantAllowed && response.Result.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", response.Result.Message, tt.wantMessage)
+			}
+			if !reflect.DeepEqual(response.Warnings, tt.wantWarnings) {
+				t.Errorf("Warnings = %q, want %q", response.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestHandler_IngressAnnotations(t *testing.T) {
+	ingress := testutil.AddAnnotationToIngress(
+		testutil.AddAnnotationToIngress(
+			testutil.CreateIngressWithHost("app", "app.example.com"),
+			annotations.ForecastleExposeAnnotation, "yes"),
+		annotations.ForecastlePropertiesAnnotation, "Version:1.0,Owner")
+
+	response := sendAdmissionReview(t, NewHandler(config.Config{}, false), ingressKind, admissionv1.Update, ingress)
+	if response.Allowed {
+		t.Fatal("Expected the Ingress to be rejected")
+	}
+	if !strings.Contains(response.Result.Message, `property "Owner" is not a key:value pair`) {
+		t.Errorf("Unexpected message %q", response.Result.Message)
+	}
+	if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "forecastle.stakater.com/expose") {
+		t.Errorf("Expected a warning about the expose annotation, got %q", response.Warnings)
+	}
+}
+
+func TestHandler_AnnotationScheme(t *testing.T) {
+	ingress := testutil.AddAnnotationToIngress(
+		testutil.AddAnnotationToIngress(
+			testutil.CreateIngressWithHost("app", "app.example.com"),
+			"dashboard.example.com/properties", "Version:1.0,Owner"),
+		annotations.ForecastlePropertiesAnnotation, "Ignored")
+	handler := NewHandler(config.Config{Annotations: config.Annotations{Prefix: "dashboard.example.com"}}, false)
+
+	response := sendAdmissionReview(t, handler, ingressKind, admissionv1.Update, ingress)
+	if response.Allowed {
+		t.Fatal("Expected the Ingress to be rejected for the annotation with the configured prefix")
+	}
+	if !strings.Contains(response.Result.Message, `property "Owner" is not a key:value pair`) {
+		t.Errorf("Unexpected message %q", response.Result.Message)
+	}
+
+	ingress.Annotations["dashboard.example.com/properties"] = "Version:1.0"
+	if response := sendAdmissionReview(t, handler, ingressKind, admissionv1.Update, ingress); !response.Allowed {
+		t.Errorf("Expected annotations with the default prefix to be ignored, got %q", response.Result.Message)
+	}
+}
+
+func TestHandler_AllowsDeletesAndOtherKinds(t *testing.T) {
+	invalid := &v1beta1.ForecastleApp{}
+	if response := sendAdmissionReview(t, NewHandler(config.Config{}, false), forecastleAppKind, admissionv1.Delete, invalid); !response.Allowed {
+		t.Error("Expected deletes to be allowed")
+	}
+	configMapKind := metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	if response := sendAdmissionReview(t, NewHandler(config.Config{}, false), configMapKind, admissionv1.Create, invalid); !response.Allowed {
+		t.Error("Expected other kinds to be allowed")
+	}
+}
+
+func TestHandler_InvalidRequest(t *testing.T) {
+	server := httptest.NewServer(NewHandler(config.Config{}, false))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"kind":"AdmissionReview"}`))
+	if err != nil {
+		t.Fatalf("Sending request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
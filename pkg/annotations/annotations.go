@@ -1,5 +1,10 @@
 package annotations
 
+import "strings"
+
+// ForecastleAnnotationPrefix is the prefix shared by all forecastle annotations
+const ForecastleAnnotationPrefix = "forecastle.stakater.com/"
+
 const (
 	// ForecastleIconAnnotation const used for forecastle icon
 	ForecastleIconAnnotation = "forecastle.stakater.com/icon"
@@ -13,8 +18,48 @@ const (
 	ForecastleInstanceAnnotation = "forecastle.stakater.com/instance"
 	// ForecastleNetworkRestrictedAnnotation const used for specifying whether the app is network restricted or not
 	ForecastleNetworkRestrictedAnnotation = "forecastle.stakater.com/network-restricted"
+	// ForecastleRequiresAuthAnnotation const used for specifying whether the app requires authentication or not
+	ForecastleRequiresAuthAnnotation = "forecastle.stakater.com/requires-auth"
+	// ForecastleAllowedGroupsAnnotation const used for a comma separated list of the user groups allowed to see the app
+	ForecastleAllowedGroupsAnnotation = "forecastle.stakater.com/allowed-groups"
 	// ForecastleURLAnnotation const used for specifying the URL for the forecastle app
 	ForecastleURLAnnotation = "forecastle.stakater.com/url"
-	// ForecastlePropertiesAnnotation const used for specifying app properties
+	// ForecastlePropertiesAnnotation const used for specifying app properties as key:value,key:value
 	ForecastlePropertiesAnnotation = "forecastle.stakater.com/properties"
+	// ForecastleStructuredPropertiesAnnotation const used for specifying app properties as a YAML or JSON object
+	ForecastleStructuredPropertiesAnnotation = "forecastle.stakater.com/properties-yaml"
+	// ForecastlePropertyAnnotationPrefix const used for specifying a single app property as property.<key>
+	ForecastlePropertyAnnotationPrefix = "forecastle.stakater.com/property."
+	// ForecastleDescriptionAnnotation const used for a short description shown on the app's tile
+	ForecastleDescriptionAnnotation = "forecastle.stakater.com/description"
+	// ForecastleTagsAnnotation const used for a comma separated list of tags to filter apps by
+	ForecastleTagsAnnotation = "forecastle.stakater.com/tags"
+	// ForecastleWeightAnnotation const used for ordering apps within their group, lower weights first
+	ForecastleWeightAnnotation = "forecastle.stakater.com/weight"
+	// ForecastleLinksAnnotation const used for secondary links of the app, such as docs, runbooks or dashboards
+	ForecastleLinksAnnotation = "forecastle.stakater.com/links"
+	// ForecastleAppIDAnnotation const used for identifying the same app discovered through multiple sources
+	ForecastleAppIDAnnotation = "forecastle.stakater.com/app-id"
+	// IngressClassAnnotation const used for the legacy ingress class annotation that predates spec.ingressClassName
+	IngressClassAnnotation = "kubernetes.io/ingress.class"
+	// NginxUseRegexAnnotation const used by ingress-nginx for interpreting the paths of an ingress as regular expressions
+	NginxUseRegexAnnotation = "nginx.ingress.kubernetes.io/use-regex"
+	// NginxRewriteTargetAnnotation const used by ingress-nginx for rewriting paths, which implies regular expression paths
+	NginxRewriteTargetAnnotation = "nginx.ingress.kubernetes.io/rewrite-target"
+	// OpenShiftDisplayNameAnnotation const used for the human friendly name OpenShift projects carry on their namespace
+	OpenShiftDisplayNameAnnotation = "openshift.io/display-name"
 )
+
+// ForecastleAnnotations returns the forecastle annotations present in annots, or nil if there are none
+func ForecastleAnnotations(annots map[string]string) map[string]string {
+	var forecastleAnnotations map[string]string
+	for key, value := range annots {
+		if strings.HasPrefix(key, ForecastleAnnotationPrefix) {
+			if forecastleAnnotations == nil {
+				forecastleAnnotations = map[string]string{}
+			}
+			forecastleAnnotations[key] = value
+		}
+	}
+	return forecastleAnnotations
+}
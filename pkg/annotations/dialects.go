@@ -0,0 +1,137 @@
+package annotations
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ForecastleDialect is the name of the dialect of forecastle's own annotations
+const ForecastleDialect = "forecastle"
+
+// Dialect maps the annotations of another dashboard to the forecastle annotations they stand for
+type Dialect struct {
+	Name string
+	// Keys maps annotation keys of the dialect to forecastle annotation keys
+	Keys map[string]string
+}
+
+// HajimariDialect reads the hajimari.io annotations of Hajimari
+var HajimariDialect = Dialect{
+	Name: "hajimari",
+	Keys: map[string]string{
+		"hajimari.io/enable":   ForecastleExposeAnnotation,
+		"hajimari.io/appName":  ForecastleAppNameAnnotation,
+		"hajimari.io/group":    ForecastleGroupAnnotation,
+		"hajimari.io/icon":     ForecastleIconAnnotation,
+		"hajimari.io/info":     ForecastleDescriptionAnnotation,
+		"hajimari.io/url":      ForecastleURLAnnotation,
+		"hajimari.io/instance": ForecastleInstanceAnnotation,
+	},
+}
+
+// HomepageDialect reads the gethomepage.dev annotations of Homepage
+var HomepageDialect = Dialect{
+	Name: "homepage",
+	Keys: map[string]string{
+		"gethomepage.dev/enabled":     ForecastleExposeAnnotation,
+		"gethomepage.dev/name":        ForecastleAppNameAnnotation,
+		"gethomepage.dev/group":       ForecastleGroupAnnotation,
+		"gethomepage.dev/icon":        ForecastleIconAnnotation,
+		"gethomepage.dev/description": ForecastleDescriptionAnnotation,
+		"gethomepage.dev/href":        ForecastleURLAnnotation,
+		"gethomepage.dev/weight":      ForecastleWeightAnnotation,
+	},
+}
+
+// Dialects are the annotation dialects that can be enabled, by name
+var Dialects = map[string]Dialect{
+	HajimariDialect.Name: HajimariDialect,
+	HomepageDialect.Name: HomepageDialect,
+}
+
+// ValidateDialects returns an error if any of names isn't a known dialect
+func ValidateDialects(names []string) error {
+	for _, name := range names {
+		if _, ok := Dialects[name]; !ok && name != ForecastleDialect {
+			return fmt.Errorf("unknown annotation dialect %q", name)
+		}
+	}
+	return nil
+}
+
+// Scheme translates the annotations of objects into forecastle annotations. It reads forecastle annotations
+// with a configurable prefix and the annotations of other dialects, in order of precedence
+type Scheme struct {
+	prefix   string
+	dialects []string
+}
+
+// NewScheme returns the scheme reading forecastle annotations with prefix, empty for the default prefix, and
+// the given dialects from highest to lowest precedence. Forecastle's own annotations have the highest precedence
+// unless ForecastleDialect is listed. Unknown dialects are ignored
+func NewScheme(prefix string, dialects []string) *Scheme {
+	if prefix == "" {
+		prefix = ForecastleAnnotationPrefix
+	} else if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	scheme := &Scheme{prefix: prefix}
+	for _, name := range dialects {
+		if _, ok := Dialects[name]; ok || name == ForecastleDialect {
+			scheme.dialects = append(scheme.dialects, name)
+		}
+	}
+	if !slices.Contains(scheme.dialects, ForecastleDialect) {
+		scheme.dialects = append([]string{ForecastleDialect}, scheme.dialects...)
+	}
+	return scheme
+}
+
+// isDefault returns true if the scheme only reads forecastle annotations with the default prefix
+func (s *Scheme) isDefault() bool {
+	return s == nil || (s.prefix == ForecastleAnnotationPrefix && len(s.dialects) == 1)
+}
+
+// Normalize returns annots with the annotations of the scheme translated into forecastle annotations with the
+// default prefix, which take the value of the highest precedence dialect setting them. Forecastle annotations
+// with the default prefix are dropped when another prefix is configured. annots is returned unchanged if the
+// scheme is the default one
+func (s *Scheme) Normalize(annots map[string]string) map[string]string {
+	if s.isDefault() || len(annots) == 0 {
+		return annots
+	}
+
+	normalized := make(map[string]string, len(annots))
+	for key, value := range annots {
+		if !strings.HasPrefix(key, ForecastleAnnotationPrefix) {
+			normalized[key] = value
+		}
+	}
+
+	for _, dialect := range s.dialects {
+		for key, value := range annots {
+			forecastleKey, ok := s.translate(dialect, key)
+			if !ok || value == "" {
+				continue
+			}
+			if _, exists := normalized[forecastleKey]; !exists {
+				normalized[forecastleKey] = value
+			}
+		}
+	}
+	return normalized
+}
+
+// translate returns the forecastle annotation key that key stands for in dialect
+func (s *Scheme) translate(dialect string, key string) (string, bool) {
+	if dialect == ForecastleDialect {
+		if !strings.HasPrefix(key, s.prefix) {
+			return "", false
+		}
+		return ForecastleAnnotationPrefix + strings.TrimPrefix(key, s.prefix), true
+	}
+	forecastleKey, ok := Dialects[dialect].Keys[key]
+	return forecastleKey, ok
+}
@@ -0,0 +1,103 @@
+package annotations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScheme_Normalize(t *testing.T) {
+	annots := map[string]string{
+		"forecastle.stakater.com/group": "Forecastle",
+		"example.com/icon":              "https://example.com/icon.png",
+		"example.com/property.owner":    "ops",
+		"hajimari.io/enable":            "true",
+		"hajimari.io/group":             "Hajimari",
+		"hajimari.io/info":              "",
+		"gethomepage.dev/group":         "Homepage",
+		"gethomepage.dev/description":   "Dashboards",
+		"kubernetes.io/ingress.class":   "nginx",
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		dialects []string
+		want     map[string]string
+	}{
+		{
+			name: "Default",
+			want: annots,
+		},
+		{
+			name:     "DialectsBelowForecastle",
+			dialects: []string{"homepage", "hajimari", "unknown"},
+			want: map[string]string{
+				"forecastle.stakater.com/group":       "Forecastle",
+				"forecastle.stakater.com/expose":      "true",
+				"forecastle.stakater.com/description": "Dashboards",
+				"example.com/icon":                    "https://example.com/icon.png",
+				"example.com/property.owner":          "ops",
+				"hajimari.io/enable":                  "true",
+				"hajimari.io/group":                   "Hajimari",
+				"hajimari.io/info":                    "",
+				"gethomepage.dev/group":               "Homepage",
+				"gethomepage.dev/description":         "Dashboards",
+				"kubernetes.io/ingress.class":         "nginx",
+			},
+		},
+		{
+			name:     "DialectAboveForecastle",
+			dialects: []string{"hajimari", "forecastle"},
+			want: map[string]string{
+				"forecastle.stakater.com/group":  "Hajimari",
+				"forecastle.stakater.com/expose": "true",
+				"example.com/icon":               "https://example.com/icon.png",
+				"example.com/property.owner":     "ops",
+				"hajimari.io/enable":             "true",
+				"hajimari.io/group":              "Hajimari",
+				"hajimari.io/info":               "",
+				"gethomepage.dev/group":          "Homepage",
+				"gethomepage.dev/description":    "Dashboards",
+				"kubernetes.io/ingress.class":    "nginx",
+			},
+		},
+		{
+			name:   "CustomPrefix",
+			prefix: "example.com",
+			want: map[string]string{
+				"forecastle.stakater.com/icon":           "https://example.com/icon.png",
+				"forecastle.stakater.com/property.owner": "ops",
+				"example.com/icon":                       "https://example.com/icon.png",
+				"example.com/property.owner":             "ops",
+				"hajimari.io/enable":                     "true",
+				"hajimari.io/group":                      "Hajimari",
+				"hajimari.io/info":                       "",
+				"gethomepage.dev/group":                  "Homepage",
+				"gethomepage.dev/description":            "Dashboards",
+				"kubernetes.io/ingress.class":            "nginx",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := NewScheme(tt.prefix, tt.dialects)
+			got := scheme.Normalize(annots)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scheme.Normalize() = %v, want %v", got, tt.want)
+			}
+			// Normalizing twice gives the same annotations
+			if again := scheme.Normalize(got); !reflect.DeepEqual(again, tt.want) {
+				t.Errorf("Scheme.Normalize() twice = %v, want %v", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDialects(t *testing.T) {
+	if err := ValidateDialects([]string{"hajimari", "forecastle", "homepage"}); err != nil {
+		t.Errorf("ValidateDialects() error = %v", err)
+	}
+	if err := ValidateDialects([]string{"heimdall"}); err == nil {
+		t.Error("ValidateDialects() expected error for an unknown dialect")
+	}
+}
@@ -0,0 +1,15 @@
+package annotations
+
+// Kubernetes recommended labels, used as defaults for the metadata of apps when recommended labels are enabled
+const (
+	// AppNameLabel const used for the name of the application
+	AppNameLabel = "app.kubernetes.io/name"
+	// AppPartOfLabel const used for the name of the higher level application this one is part of
+	AppPartOfLabel = "app.kubernetes.io/part-of"
+	// AppVersionLabel const used for the current version of the application
+	AppVersionLabel = "app.kubernetes.io/version"
+	// AppComponentLabel const used for the component within the architecture
+	AppComponentLabel = "app.kubernetes.io/component"
+	// AppManagedByLabel const used for the tool being used to manage the operation of the application
+	AppManagedByLabel = "app.kubernetes.io/managed-by"
+)
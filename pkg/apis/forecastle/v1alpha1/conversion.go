@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	"maps"
+
+	"github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+)
+
+// ConvertTo converts the ForecastleApp to its v1beta1 representation. Properties become text properties
+func (in *ForecastleApp) ConvertTo(out *v1beta1.ForecastleApp) {
+	out.TypeMeta = in.TypeMeta
+	if out.Kind != "" {
+		out.APIVersion = v1beta1.SchemeGroupVersion.String()
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+
+	out.Spec = v1beta1.ForecastleAppSpec{
+		Name:              in.Spec.Name,
+		Instance:          in.Spec.Instance,
+		Group:             in.Spec.Group,
+		Icon:              in.Spec.Icon,
+		URL:               in.Spec.URL,
+		AppID:             in.Spec.AppID,
+		NetworkRestricted: setOnlyIfTrue(in.Spec.NetworkRestricted),
+	}
+	if in.Spec.URLFrom != nil {
+		out.Spec.URLFrom = &v1beta1.URLSource{}
+		if ref := in.Spec.URLFrom.IngressRef; ref != nil {
+			out.Spec.URLFrom.IngressRef = &v1beta1.IngressURLSource{ObjectReference: v1beta1.ObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.RouteRef; ref != nil {
+			out.Spec.URLFrom.RouteRef = &v1beta1.RouteURLSource{ObjectReference: v1beta1.ObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.IngressRouteRef; ref != nil {
+			out.Spec.URLFrom.IngressRouteRef = &v1beta1.IngressRouteURLSource{ObjectReference: v1beta1.ObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.HTTPRouteRef; ref != nil {
+			out.Spec.URLFrom.HTTPRouteRef = &v1beta1.HTTPRouteURLSource{ObjectReference: v1beta1.ObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.ServiceRef; ref != nil {
+			out.Spec.URLFrom.ServiceRef = &v1beta1.ServiceURLSource{
+				ObjectReference: v1beta1.ObjectReference{Name: ref.Name},
+				Port:            ref.DeepCopy().Port,
+				Scheme:          ref.Scheme,
+				Path:            ref.Path,
+			}
+		}
+	}
+	out.Spec.Properties = maps.Clone(in.Spec.Properties)
+
+	status := in.Status.DeepCopy()
+	out.Status = v1beta1.ForecastleAppStatus{
+		ResolvedURL:        status.ResolvedURL,
+		ObservedGeneration: status.ObservedGeneration,
+		LastDiscoveredTime: status.LastDiscoveredTime,
+		Conditions:         status.Conditions,
+	}
+}
+
+// ConvertFrom converts a v1beta1 ForecastleApp to its v1alpha1 representation. Fields v1alpha1 has no place
+// for are dropped, including the namespaces of URL references and the types of properties
+func (out *ForecastleApp) ConvertFrom(in *v1beta1.ForecastleApp) {
+	out.TypeMeta = in.TypeMeta
+	if out.Kind != "" {
+		out.APIVersion = SchemeGroupVersion.String()
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+
+	out.Spec = ForecastleAppSpec{
+		Name:              in.Spec.Name,
+		Instance:          in.Spec.Instance,
+		Group:             in.Spec.Group,
+		Icon:              in.Spec.Icon,
+		URL:               in.Spec.URL,
+		AppID:             in.Spec.AppID,
+		NetworkRestricted: in.Spec.NetworkRestricted != nil && *in.Spec.NetworkRestricted,
+	}
+	if in.Spec.URLFrom != nil {
+		out.Spec.URLFrom = &URLSource{}
+		if ref := in.Spec.URLFrom.IngressRef; ref != nil {
+			out.Spec.URLFrom.IngressRef = &IngressURLSource{LocalObjectReference: LocalObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.RouteRef; ref != nil {
+			out.Spec.URLFrom.RouteRef = &RouteURLSource{LocalObjectReference: LocalObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.IngressRouteRef; ref != nil {
+			out.Spec.URLFrom.IngressRouteRef = &IngressRouteURLSource{LocalObjectReference: LocalObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.HTTPRouteRef; ref != nil {
+			out.Spec.URLFrom.HTTPRouteRef = &HTTPRouteURLSource{LocalObjectReference: LocalObjectReference{Name: ref.Name}}
+		}
+		if ref := in.Spec.URLFrom.ServiceRef; ref != nil {
+			out.Spec.URLFrom.ServiceRef = &ServiceURLSource{
+				LocalObjectReference: LocalObjectReference{Name: ref.Name},
+				Port:                 ref.DeepCopy().Port,
+				Scheme:               ref.Scheme,
+				Path:                 ref.Path,
+			}
+		}
+	}
+	out.Spec.Properties = maps.Clone(in.Spec.Properties)
+
+	status := in.Status.DeepCopy()
+	out.Status = ForecastleAppStatus{
+		ResolvedURL:        status.ResolvedURL,
+		ObservedGeneration: status.ObservedGeneration,
+		LastDiscoveredTime: status.LastDiscoveredTime,
+		Conditions:         status.Conditions,
+	}
+}
+
+// setOnlyIfTrue returns a pointer to value if it's true and nil otherwise. v1alpha1 can't tell an unset flag
+// from false, so only true overrides what v1beta1 detects
+func setOnlyIfTrue(value bool) *bool {
+	if !value {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,125 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestForecastleApp_ConvertTo(t *testing.T) {
+	port := intstr.FromString("https")
+	in := &ForecastleApp{
+		TypeMeta:   metav1.TypeMeta{Kind: "ForecastleApp", APIVersion: SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 2},
+		Spec: ForecastleAppSpec{
+			Name:  "App",
+			Group: "dev",
+			Icon:  "https://icon",
+			URLFrom: &URLSource{
+				IngressRef: &IngressURLSource{LocalObjectReference: LocalObjectReference{Name: "app-ingress"}},
+				ServiceRef: &ServiceURLSource{LocalObjectReference: LocalObjectReference{Name: "app"}, Port: &port, Path: "/ui"},
+			},
+			Properties:        map[string]string{"Version": "1.0"},
+			NetworkRestricted: true,
+		},
+		Status: ForecastleAppStatus{ResolvedURL: "https://app.example.com", ObservedGeneration: 2},
+	}
+
+	out := &v1beta1.ForecastleApp{}
+	in.ConvertTo(out)
+	networkRestricted := true
+
+	want := &v1beta1.ForecastleApp{
+		TypeMeta:   metav1.TypeMeta{Kind: "ForecastleApp", APIVersion: v1beta1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 2},
+		Spec: v1beta1.ForecastleAppSpec{
+			Name:  "App",
+			Group: "dev",
+			Icon:  "https://icon",
+			URLFrom: &v1beta1.URLSource{
+				IngressRef: &v1beta1.IngressURLSource{ObjectReference: v1beta1.ObjectReference{Name: "app-ingress"}},
+				ServiceRef: &v1beta1.ServiceURLSource{ObjectReference: v1beta1.ObjectReference{Name: "app"}, Port: &port, Path: "/ui"},
+			},
+			Properties:        map[string]string{"Version": "1.0"},
+			NetworkRestricted: &networkRestricted,
+		},
+		Status: v1beta1.ForecastleAppStatus{ResolvedURL: "https://app.example.com", ObservedGeneration: 2},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ConvertTo() = %+v, want %+v", out, want)
+	}
+}
+
+func TestForecastleApp_ConvertFrom(t *testing.T) {
+	in := &v1beta1.ForecastleApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: v1beta1.ForecastleAppSpec{
+			Name:          "App",
+			URL:           "https://app.example.com",
+			Description:   "Dropped in v1alpha1",
+			Tags:          []string{"dropped"},
+			Properties:    map[string]string{"Docs": "https://docs.example.com", "Version": "1.0"},
+			PropertyTypes: map[string]v1beta1.PropertyType{"Docs": v1beta1.PropertyTypeURL},
+		},
+	}
+
+	out := &ForecastleApp{}
+	out.ConvertFrom(in)
+
+	want := &ForecastleApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: ForecastleAppSpec{
+			Name:       "App",
+			URL:        "https://app.example.com",
+			Properties: map[string]string{"Docs": "https://docs.example.com", "Version": "1.0"},
+		},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ConvertFrom() = %+v, want %+v", out, want)
+	}
+}
+
+// The CRD doesn't convert between versions, so objects stored as either version must decode as the other
+func TestForecastleApp_StoredShapeReadableByBothVersions(t *testing.T) {
+	port := intstr.FromInt32(8080)
+	stored, err := json.Marshal(&v1beta1.ForecastleApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: v1beta1.ForecastleAppSpec{
+			Name:          "App",
+			URLFrom:       &v1beta1.URLSource{ServiceRef: &v1beta1.ServiceURLSource{ObjectReference: v1beta1.ObjectReference{Name: "app"}, Port: &port}},
+			Links:         []v1beta1.Link{{Label: "Runbook", URL: "https://wiki.example.com/app"}},
+			Properties:    map[string]string{"Docs": "https://docs.example.com", "Replicas": "3"},
+			PropertyTypes: map[string]v1beta1.PropertyType{"Docs": v1beta1.PropertyTypeURL, "Replicas": v1beta1.PropertyTypeNumber},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode v1beta1 ForecastleApp: %v", err)
+	}
+
+	var alpha ForecastleApp
+	if err := json.Unmarshal(stored, &alpha); err != nil {
+		t.Fatalf("v1alpha1 can't decode a stored v1beta1 ForecastleApp: %v", err)
+	}
+	if want := map[string]string{"Docs": "https://docs.example.com", "Replicas": "3"}; !reflect.DeepEqual(alpha.Spec.Properties, want) {
+		t.Errorf("v1alpha1 properties = %v, want %v", alpha.Spec.Properties, want)
+	}
+	if alpha.Spec.URLFrom == nil || alpha.Spec.URLFrom.ServiceRef == nil || alpha.Spec.URLFrom.ServiceRef.Name != "app" {
+		t.Errorf("v1alpha1 urlFrom = %+v, want the service reference", alpha.Spec.URLFrom)
+	}
+
+	stored, err = json.Marshal(&alpha)
+	if err != nil {
+		t.Fatalf("Failed to encode v1alpha1 ForecastleApp: %v", err)
+	}
+	var beta v1beta1.ForecastleApp
+	if err := json.Unmarshal(stored, &beta); err != nil {
+		t.Fatalf("v1beta1 can't decode a stored v1alpha1 ForecastleApp: %v", err)
+	}
+	if !reflect.DeepEqual(beta.Spec.Properties, alpha.Spec.Properties) {
+		t.Errorf("v1beta1 properties = %v, want %v", beta.Spec.Properties, alpha.Spec.Properties)
+	}
+}
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // ForecastleAppSpec defines the desired state of ForecastleApp
@@ -13,6 +14,8 @@ type ForecastleAppSpec struct {
 	Icon     string `json:"icon"`
 	URL      string `json:"url,omitempty"`
 	// +optional
+	AppID string `json:"appId,omitempty"`
+	// +optional
 	URLFrom *URLSource `json:"urlFrom,omitempty"`
 	// +optional
 	NetworkRestricted bool `json:"networkRestricted,omitempty"`
@@ -30,6 +33,8 @@ type URLSource struct {
 	IngressRouteRef *IngressRouteURLSource `json:"ingressRouteRef,omitempty"`
 	// +optional
 	HTTPRouteRef *HTTPRouteURLSource `json:"httpRouteRef,omitempty"`
+	// +optional
+	ServiceRef *ServiceURLSource `json:"serviceRef,omitempty"`
 }
 
 // IngressURLSource selects an Ingress to populate the URL with
@@ -52,6 +57,20 @@ type HTTPRouteURLSource struct {
 	LocalObjectReference
 }
 
+// ServiceURLSource selects a LoadBalancer or NodePort Service to populate the URL with
+type ServiceURLSource struct {
+	LocalObjectReference `json:",inline"`
+	// Port is the name or number of the service port, defaults to the first port
+	// +optional
+	Port *intstr.IntOrString `json:"port,omitempty"`
+	// Scheme defaults to https for port 443 or ports named https, and http otherwise
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+	// Path is appended to the URL
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
 // LocalObjectReference contains enough information to let you locate the referenced object inside the same namespace.
 type LocalObjectReference struct {
 	Name string `json:"name"`
@@ -60,7 +79,20 @@ type LocalObjectReference struct {
 // ForecastleAppStatus defines the observed state of ForecastleApp
 // +k8s:openapi-gen=true
 type ForecastleAppStatus struct {
-	// Add status fields if required
+	// ResolvedURL is the URL forecastle shows for the app
+	// +optional
+	ResolvedURL string `json:"resolvedURL,omitempty"`
+	// ObservedGeneration is the generation of the spec the status was computed from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastDiscoveredTime is the last time forecastle discovered the app
+	// +optional
+	LastDiscoveredTime *metav1.Time `json:"lastDiscoveredTime,omitempty"`
+	// Conditions holds the Ready condition of the app
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // ForecastleApp is the Schema for the forecastleapps API
@@ -68,6 +100,10 @@ type ForecastleAppStatus struct {
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.resolvedURL`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type ForecastleApp struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
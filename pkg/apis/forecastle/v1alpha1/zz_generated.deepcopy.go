@@ -22,7 +22,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -31,7 +33,7 @@ func (in *ForecastleApp) DeepCopyInto(out *ForecastleApp) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -117,6 +119,17 @@ func (in *ForecastleAppSpec) DeepCopy() *ForecastleAppSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ForecastleAppStatus) DeepCopyInto(out *ForecastleAppStatus) {
 	*out = *in
+	if in.LastDiscoveredTime != nil {
+		in, out := &in.LastDiscoveredTime, &out.LastDiscoveredTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -197,6 +210,28 @@ func (in *IngressRouteURLSource) DeepCopy() *IngressRouteURLSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceURLSource) DeepCopyInto(out *ServiceURLSource) {
+	*out = *in
+	out.LocalObjectReference = in.LocalObjectReference
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceURLSource.
+func (in *ServiceURLSource) DeepCopy() *ServiceURLSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceURLSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *URLSource) DeepCopyInto(out *URLSource) {
 	*out = *in
@@ -210,6 +245,11 @@ func (in *URLSource) DeepCopyInto(out *URLSource) {
 		*out = new(RouteURLSource)
 		**out = **in
 	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(ServiceURLSource)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
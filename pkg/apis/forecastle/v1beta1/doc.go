@@ -0,0 +1,5 @@
+// Package v1beta1 contains API Schema definitions for the forecastle v1beta1 API group
+// +k8s:deepcopy-gen=package,register
+// +k8s:defaulter-gen=TypeMeta
+// +groupName=forecastle.stakater.com
+package v1beta1
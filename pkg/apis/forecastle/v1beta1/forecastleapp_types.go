@@ -0,0 +1,197 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ForecastleAppSpec defines the desired state of ForecastleApp
+// +k8s:openapi-gen=true
+type ForecastleAppSpec struct {
+	Name string `json:"name"`
+	// +optional
+	Instance string `json:"instance,omitempty"`
+	// Group defaults to the group of the namespace when empty
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Icon defaults to the icon of the namespace when empty
+	// +optional
+	Icon string `json:"icon,omitempty"`
+	// +optional
+	URL string `json:"url,omitempty"`
+	// +optional
+	AppID string `json:"appId,omitempty"`
+	// +optional
+	URLFrom *URLSource `json:"urlFrom,omitempty"`
+	// Description is a short line shown on the app's tile
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// Weight orders apps within their group, lower weights first
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+	// Links are secondary links of the app such as docs, runbooks or dashboards
+	// +optional
+	Links []Link `json:"links,omitempty"`
+	// NetworkRestricted overrides whether the app only accepts clients from some networks. If unset, it's
+	// detected from the ingress controller configuration of the object the URL is discovered from
+	// +optional
+	NetworkRestricted *bool `json:"networkRestricted,omitempty"`
+	// AllowedGroups are the user groups allowed to see the app. Defaults to the allowed groups of the
+	// namespace, and to everyone if those are empty too
+	// +optional
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+	// Properties are plain strings as in v1alpha1, so v1alpha1 clients can read the objects stored by v1beta1
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+	// PropertyTypes sets the types of properties, keyed like Properties. Properties without a type are text
+	// +optional
+	PropertyTypes map[string]PropertyType `json:"propertyTypes,omitempty"`
+}
+
+// Link is a labelled secondary link of an app
+type Link struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+	// +optional
+	Icon string `json:"icon,omitempty"`
+}
+
+// PropertyType is the type of a property value
+type PropertyType string
+
+// Supported property types
+const (
+	PropertyTypeText    PropertyType = "text"
+	PropertyTypeURL     PropertyType = "url"
+	PropertyTypeNumber  PropertyType = "number"
+	PropertyTypeBoolean PropertyType = "boolean"
+)
+
+// URLSource represents the set of resources to fetch the URL from
+type URLSource struct {
+	// +optional
+	IngressRef *IngressURLSource `json:"ingressRef,omitempty"`
+	// +optional
+	RouteRef *RouteURLSource `json:"routeRef,omitempty"`
+	// +optional
+	IngressRouteRef *IngressRouteURLSource `json:"ingressRouteRef,omitempty"`
+	// +optional
+	HTTPRouteRef *HTTPRouteURLSource `json:"httpRouteRef,omitempty"`
+	// +optional
+	ServiceRef *ServiceURLSource `json:"serviceRef,omitempty"`
+}
+
+// IngressURLSource selects an Ingress to populate the URL with
+type IngressURLSource struct {
+	ObjectReference
+}
+
+// RouteURLSource selects a Route to populate the URL with
+type RouteURLSource struct {
+	ObjectReference
+}
+
+// IngressRouteURLSource selects an IngressRoute to populate the URL with
+type IngressRouteURLSource struct {
+	ObjectReference
+}
+
+// HTTPRouteURLSource selects a Gateway API HTTPRoute to populate the URL with
+type HTTPRouteURLSource struct {
+	ObjectReference
+}
+
+// ServiceURLSource selects a LoadBalancer or NodePort Service to populate the URL with
+type ServiceURLSource struct {
+	ObjectReference `json:",inline"`
+	// Port is the name or number of the service port, defaults to the first port
+	// +optional
+	Port *intstr.IntOrString `json:"port,omitempty"`
+	// Scheme defaults to https for port 443 or ports named https, and http otherwise
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+	// Path is appended to the URL
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ObjectReference contains enough information to let you locate the referenced object. Objects in another
+// namespace than the ForecastleApp's must be allowed by a Gateway API ReferenceGrant in their namespace.
+type ObjectReference struct {
+	Name string `json:"name"`
+	// Namespace defaults to the namespace of the ForecastleApp
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ForecastleAppStatus defines the observed state of ForecastleApp
+// +k8s:openapi-gen=true
+type ForecastleAppStatus struct {
+	// ResolvedURL is the URL forecastle shows for the app
+	// +optional
+	ResolvedURL string `json:"resolvedURL,omitempty"`
+	// ObservedGeneration is the generation of the spec the status was computed from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastDiscoveredTime is the last time forecastle discovered the app
+	// +optional
+	LastDiscoveredTime *metav1.Time `json:"lastDiscoveredTime,omitempty"`
+	// Conditions holds the Ready condition of the app
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ForecastleAppConditionReady is the condition type reporting whether the app's URL could be resolved
+const ForecastleAppConditionReady = "Ready"
+
+// Reasons of the Ready condition
+const (
+	ReasonURLResolved                = "URLResolved"
+	ReasonURLNotResolvable           = "URLNotResolvable"
+	ReasonURLSourceMissing           = "URLSourceMissing"
+	ReasonURLSourceUnsupported       = "URLSourceUnsupported"
+	ReasonIngressNotFound            = "IngressNotFound"
+	ReasonRouteNotFound              = "RouteNotFound"
+	ReasonIngressRouteNotFound       = "IngressRouteNotFound"
+	ReasonHTTPRouteNotFound          = "HTTPRouteNotFound"
+	ReasonServiceNotFound            = "ServiceNotFound"
+	ReasonServiceNotExposed          = "ServiceNotExposed"
+	ReasonRouteAPIUnavailable        = "RouteAPIUnavailable"
+	ReasonIngressRouteAPIUnavailable = "IngressRouteAPIUnavailable"
+	ReasonGatewayAPIUnavailable      = "GatewayAPIUnavailable"
+	ReasonURLDiscoveryFailed         = "URLDiscoveryFailed"
+	ReasonRefNotPermitted            = "RefNotPermitted"
+)
+
+// ForecastleApp is the Schema for the forecastleapps API
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.resolvedURL`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type ForecastleApp struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ForecastleAppSpec   `json:"spec,omitempty"`
+	Status ForecastleAppStatus `json:"status,omitempty"`
+}
+
+// Hub marks v1beta1 as the version other ForecastleApp versions convert to and from
+func (*ForecastleApp) Hub() {}
+
+// ForecastleAppList contains a list of ForecastleApp
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ForecastleAppList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ForecastleApp `json:"items"`
+}
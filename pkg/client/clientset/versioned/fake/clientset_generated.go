@@ -22,6 +22,8 @@ import (
 	clientset "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned"
 	forecastlev1alpha1 "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/typed/forecastle/v1alpha1"
 	fakeforecastlev1alpha1 "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/typed/forecastle/v1alpha1/fake"
+	forecastlev1beta1 "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/typed/forecastle/v1beta1"
+	fakeforecastlev1beta1 "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/typed/forecastle/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -80,3 +82,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) ForecastleV1alpha1() forecastlev1alpha1.ForecastleV1alpha1Interface {
 	return &fakeforecastlev1alpha1.FakeForecastleV1alpha1{Fake: &c.Fake}
 }
+
+// ForecastleV1beta1 retrieves the ForecastleV1beta1Client
+func (c *Clientset) ForecastleV1beta1() forecastlev1beta1.ForecastleV1beta1Interface {
+	return &fakeforecastlev1beta1.FakeForecastleV1beta1{Fake: &c.Fake}
+}
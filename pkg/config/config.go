@@ -1,7 +1,15 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,9 +20,35 @@ type Config struct {
 	HeaderForeground  string            `yaml:"headerForeground" json:"headerForeground"`
 	Title             string            `yaml:"title" json:"title"`
 	InstanceName      string            `yaml:"instanceName" json:"instanceName"`
-	CustomApps        []CustomApp       `yaml:"customApps" json:"customApps"`
+	ClusterName       string            `yaml:"clusterName" json:"clusterName"`
+	CustomApps        []CustomApp       `yaml:"customApps" json:"-"`
 	CRDEnabled        bool              `yaml:"crdEnabled" json:"crdEnabled"`
+	CRDStatusEnabled  bool              `yaml:"crdStatusEnabled" json:"crdStatusEnabled"`
 	BasePath          string            `yaml:"basePath" json:"basePath"`
+	IngressClasses    []string          `yaml:"ingressClasses" json:"ingressClasses"`
+	GatewayClasses    []string          `yaml:"gatewayClasses" json:"gatewayClasses"`
+	Deduplication     Deduplication     `yaml:"deduplication" json:"deduplication"`
+	// Groups configures how groups are shown, keyed by group slug
+	Groups         map[string]Group `yaml:"groups" json:"groups,omitempty"`
+	GroupHierarchy GroupHierarchy   `yaml:"groupHierarchy" json:"groupHierarchy"`
+	// Annotations configures the prefix of forecastle annotations and the annotations of other dashboards to read
+	Annotations Annotations `yaml:"annotations" json:"annotations"`
+	// RecommendedLabels uses the app.kubernetes.io labels of objects as defaults for the metadata of their apps
+	RecommendedLabels bool `yaml:"recommendedLabels" json:"recommendedLabels"`
+	// Derivation derives the name and group of apps discovered without a name or group annotation
+	Derivation Derivation `yaml:"derivation" json:"derivation"`
+	// URLRewrites rewrite the URLs of discovered apps, e.g. from internal hosts to the hosts of an external proxy
+	URLRewrites []URLRewrite `yaml:"urlRewrites" json:"urlRewrites,omitempty"`
+	// NodeAddress is the host used for the URLs of NodePort services referenced by ForecastleApps
+	NodeAddress string `yaml:"nodeAddress" json:"nodeAddress"`
+	// AccessDetection detects network restricted apps and apps requiring authentication from the
+	// configuration of their ingress controller
+	AccessDetection AccessDetection `yaml:"accessDetection" json:"accessDetection"`
+	// ClientNetworks hides network restricted apps from clients outside the internal networks. It isn't
+	// returned by /api/config
+	ClientNetworks ClientNetworks `yaml:"clientNetworks" json:"-"`
+	// Auth configures login to the dashboard. It isn't returned by /api/config
+	Auth Auth `yaml:"auth" json:"-"`
 }
 
 // CustomApp struct for specifying apps that are not generated using ingresses
@@ -23,8 +57,384 @@ type CustomApp struct {
 	Icon              string            `yaml:"icon" json:"icon"`
 	URL               string            `yaml:"url" json:"url"`
 	Group             string            `yaml:"group" json:"group"`
+	Description       string            `yaml:"description" json:"description"`
+	Tags              []string          `yaml:"tags" json:"tags"`
+	Weight            int               `yaml:"weight" json:"weight"`
+	Links             []Link            `yaml:"links" json:"links"`
+	AppID             string            `yaml:"appId" json:"appId"`
 	NetworkRestricted bool              `yaml:"networkRestricted" json:"networkRestricted"`
+	RequiresAuth      bool              `yaml:"requiresAuth" json:"requiresAuth"`
 	Properties        map[string]string `yaml:"properties" json:"properties"`
+	// AllowedGroups are the user groups allowed to see the app, everyone if empty
+	AllowedGroups []string `yaml:"allowedGroups" json:"-"`
+}
+
+// Link struct for a secondary link of a custom app
+type Link struct {
+	Label string `yaml:"label" json:"label"`
+	URL   string `yaml:"url" json:"url"`
+	Icon  string `yaml:"icon" json:"icon"`
+}
+
+// Group struct for configuring how a group of apps is shown
+type Group struct {
+	DisplayName string `yaml:"displayName" json:"displayName,omitempty"`
+	Icon        string `yaml:"icon" json:"icon,omitempty"`
+	// Order sorts groups, lower first. Groups without an order have order 0 and are sorted by slug
+	Order       int    `yaml:"order" json:"order"`
+	Description string `yaml:"description" json:"description,omitempty"`
+	// Collapsed shows the group collapsed by default
+	Collapsed bool `yaml:"collapsed" json:"collapsed"`
+}
+
+// GroupHierarchy struct for nesting groups, e.g. platform/observability inside platform
+type GroupHierarchy struct {
+	// Separator splits group names into nested groups, defaults to "/"
+	Separator string `yaml:"separator" json:"separator,omitempty"`
+	// NamespaceRules nest groups derived from a namespace under a parent group. The first matching rule applies
+	NamespaceRules []NamespaceGroupRule `yaml:"namespaceRules" json:"namespaceRules,omitempty"`
+}
+
+// NamespaceGroupRule struct for nesting the groups of the namespaces matching a regular expression
+type NamespaceGroupRule struct {
+	// Namespace is a regular expression matched against the namespace name
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// Parent is the parent group, which can refer to capture groups of Namespace as $1 or ${name}
+	Parent string `yaml:"parent" json:"parent"`
+}
+
+// Annotations struct for reading forecastle annotations with another prefix, and annotations of other dashboards
+type Annotations struct {
+	// Prefix replaces the forecastle.stakater.com/ prefix of forecastle annotations
+	Prefix string `yaml:"prefix" json:"prefix,omitempty"`
+	// Dialects lists the annotation dialects to read, from highest to lowest precedence. forecastle annotations
+	// have the highest precedence unless "forecastle" is listed
+	Dialects []string `yaml:"dialects" json:"dialects,omitempty"`
+}
+
+// GetAnnotationScheme returns the scheme translating the annotations of objects into forecastle annotations
+func (c Config) GetAnnotationScheme() *annotations.Scheme {
+	return annotations.NewScheme(c.Annotations.Prefix, c.Annotations.Dialects)
+}
+
+// URLRewrite struct for a rule rewriting the URLs of the apps in its scope whose host matches
+type URLRewrite struct {
+	// Host is a regular expression matched against the host of the URL, empty to match every host
+	Host string `yaml:"host" json:"host,omitempty"`
+	// Replacement replaces the matches of Host in the host, and can refer to capture groups as $1 or ${name}
+	Replacement string `yaml:"replacement" json:"replacement,omitempty"`
+	ForceHTTPS  bool   `yaml:"forceHttps" json:"forceHttps,omitempty"`
+	StripPort   bool   `yaml:"stripPort" json:"stripPort,omitempty"`
+	// PathPrefix is prepended to the path of the URL
+	PathPrefix string `yaml:"pathPrefix" json:"pathPrefix,omitempty"`
+	// Namespaces limits the rule to apps discovered in these namespaces
+	Namespaces []string `yaml:"namespaces" json:"namespaces,omitempty"`
+	// Sources limits the rule to apps from these discovery sources
+	Sources []string `yaml:"sources" json:"sources,omitempty"`
+}
+
+// Derivation struct for Go templates deriving names and groups from the metadata of the object an app
+// is discovered from. The first template rendering a non empty value is used
+type Derivation struct {
+	Name  []string `yaml:"name" json:"name,omitempty"`
+	Group []string `yaml:"group" json:"group,omitempty"`
+}
+
+// AccessDetection struct for detecting access restrictions from ingress controller annotations and middlewares
+type AccessDetection struct {
+	Disabled bool `yaml:"disabled" json:"disabled"`
+	// Detectors lists the detectors to run, defaults to nginx and traefik
+	Detectors []string `yaml:"detectors" json:"detectors,omitempty"`
+}
+
+// GetAccessDetectors returns the names of the access detectors to run
+func (c Config) GetAccessDetectors() []string {
+	if len(c.AccessDetection.Detectors) == 0 {
+		return []string{"nginx", "traefik"}
+	}
+	return c.AccessDetection.Detectors
+}
+
+const (
+	// ClientNetworkModeHide hides network restricted apps from external clients
+	ClientNetworkModeHide = "hide"
+	// ClientNetworkModeMark marks network restricted apps as unreachable for external clients
+	ClientNetworkModeMark = "mark"
+)
+
+// ClientNetworks struct for telling internal clients, which can reach network restricted apps, from external ones
+type ClientNetworks struct {
+	// Internal lists the CIDRs or addresses of internal clients. Client aware visibility is disabled if empty
+	Internal []string `yaml:"internal" json:"internal,omitempty"`
+	// TrustedProxies lists the CIDRs or addresses of proxies whose X-Forwarded-For header is trusted
+	TrustedProxies []string `yaml:"trustedProxies" json:"trustedProxies,omitempty"`
+	// Mode is what happens to network restricted apps for external clients, hide or mark. Defaults to hide
+	Mode string `yaml:"mode" json:"mode,omitempty"`
+}
+
+// Parse returns the internal networks and the networks of trusted proxies. Addresses are parsed as single
+// address networks
+func (n ClientNetworks) Parse() (internal []netip.Prefix, trustedProxies []netip.Prefix, err error) {
+	if n.Mode != "" && n.Mode != ClientNetworkModeHide && n.Mode != ClientNetworkModeMark {
+		return nil, nil, fmt.Errorf("unknown client network mode %q, must be %q or %q", n.Mode, ClientNetworkModeHide, ClientNetworkModeMark)
+	}
+	if internal, err = parsePrefixes(n.Internal); err != nil {
+		return nil, nil, fmt.Errorf("invalid internal client network: %w", err)
+	}
+	if trustedProxies, err = parsePrefixes(n.TrustedProxies); err != nil {
+		return nil, nil, fmt.Errorf("invalid trusted proxy network: %w", err)
+	}
+	return internal, trustedProxies, nil
+}
+
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+const (
+	// OIDCClientSecretEnv is the environment variable holding the OIDC client secret
+	OIDCClientSecretEnv = "FORECASTLE_OIDC_CLIENT_SECRET"
+	// OIDCCookieSecretEnv is the environment variable holding the secret session cookies are encrypted with
+	OIDCCookieSecretEnv = "FORECASTLE_OIDC_COOKIE_SECRET"
+)
+
+// Auth struct for configuring login to the dashboard
+type Auth struct {
+	OIDC OIDC `yaml:"oidc" json:"oidc"`
+	// Proxy identifies users by the headers of an authenticating proxy in front of the dashboard
+	Proxy AuthProxy `yaml:"proxy" json:"proxy"`
+	// RBAC shows users only the apps in namespaces they can access in the cluster
+	RBAC RBAC `yaml:"rbac" json:"rbac"`
+	// API authenticates requests to /api/* with static credentials, e.g. for automation
+	API StaticAuth `yaml:"api" json:"api"`
+	// UI authenticates requests to the dashboard outside /api/* with static credentials
+	UI StaticAuth `yaml:"ui" json:"ui"`
+}
+
+// StaticAuth struct for authenticating requests with bearer tokens or basic auth. The files are read again
+// when they change, so credentials can be rotated by updating the Secret they are mounted from
+type StaticAuth struct {
+	// BearerTokenFile holds the accepted bearer tokens, one per line
+	BearerTokenFile string `yaml:"bearerTokenFile" json:"bearerTokenFile,omitempty"`
+	// HtpasswdFile holds the users accepted with basic auth and their bcrypt hashed passwords, as written
+	// by htpasswd -B
+	HtpasswdFile string `yaml:"htpasswdFile" json:"htpasswdFile,omitempty"`
+	// User and Groups identify all requests authenticated with the credentials, for allowed groups and RBAC
+	// visibility. The user defaults to forecastle:<scope>. The users of the htpasswd file aren't used, so they
+	// can't be mistaken for cluster users
+	User   string   `yaml:"user" json:"user,omitempty"`
+	Groups []string `yaml:"groups" json:"groups,omitempty"`
+}
+
+// IsEnabled returns true if any credentials are configured
+func (s StaticAuth) IsEnabled() bool {
+	return s.BearerTokenFile != "" || s.HtpasswdFile != ""
+}
+
+// GetUser returns the user requests authenticated with the credentials of scope (api or ui) are identified as
+func (s StaticAuth) GetUser(scope string) string {
+	if s.User == "" {
+		return "forecastle:" + scope
+	}
+	return s.User
+}
+
+// RBAC struct for showing users only the apps in namespaces they have access to, checked with
+// SubjectAccessReviews. Apps that aren't discovered from a namespace, such as custom apps, aren't checked
+type RBAC struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Verb, APIGroup and Resource are the access users need in the namespace of an app, defaults to get services
+	Verb     string `yaml:"verb" json:"verb,omitempty"`
+	APIGroup string `yaml:"apiGroup" json:"apiGroup,omitempty"`
+	Resource string `yaml:"resource" json:"resource,omitempty"`
+	// UsernamePrefix and GroupsPrefix are prepended to the user and its groups, like the OIDC prefixes of the
+	// API server, so users are reviewed as the cluster knows them
+	UsernamePrefix string `yaml:"usernamePrefix" json:"usernamePrefix,omitempty"`
+	GroupsPrefix   string `yaml:"groupsPrefix" json:"groupsPrefix,omitempty"`
+	// CacheDuration is how long the results are cached per user, defaults to 5m
+	CacheDuration time.Duration `yaml:"cacheDuration" json:"cacheDuration,omitempty"`
+}
+
+// GetVerb returns the verb users need in the namespace of an app
+func (r RBAC) GetVerb() string {
+	if r.Verb == "" {
+		return "get"
+	}
+	return r.Verb
+}
+
+// GetResource returns the resource users need access to in the namespace of an app
+func (r RBAC) GetResource() string {
+	if r.Resource == "" {
+		return "services"
+	}
+	return r.Resource
+}
+
+// GetCacheDuration returns how long the results are cached per user
+func (r RBAC) GetCacheDuration() time.Duration {
+	if r.CacheDuration <= 0 {
+		return 5 * time.Minute
+	}
+	return r.CacheDuration
+}
+
+const (
+	// DefaultAuthProxyUserHeader is the header authenticating proxies pass the user in
+	DefaultAuthProxyUserHeader = "X-Forwarded-User"
+	// DefaultAuthProxyGroupsHeader is the header authenticating proxies pass the comma separated groups of the user in
+	DefaultAuthProxyGroupsHeader = "X-Forwarded-Groups"
+)
+
+// AuthProxy struct for trusting the user and groups headers set by an authenticating proxy, such as oauth2-proxy
+type AuthProxy struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// UserHeader is the header holding the user, defaults to X-Forwarded-User
+	UserHeader string `yaml:"userHeader" json:"userHeader,omitempty"`
+	// GroupsHeader is the header holding the comma separated groups of the user, defaults to X-Forwarded-Groups
+	GroupsHeader string `yaml:"groupsHeader" json:"groupsHeader,omitempty"`
+	// TrustedProxies lists the CIDRs or addresses of the proxies whose headers are trusted. Headers of other
+	// clients are ignored, so they can't claim to be someone else
+	TrustedProxies []string `yaml:"trustedProxies" json:"trustedProxies,omitempty"`
+}
+
+// GetUserHeader returns the header holding the user
+func (p AuthProxy) GetUserHeader() string {
+	if p.UserHeader == "" {
+		return DefaultAuthProxyUserHeader
+	}
+	return p.UserHeader
+}
+
+// GetGroupsHeader returns the header holding the groups of the user
+func (p AuthProxy) GetGroupsHeader() string {
+	if p.GroupsHeader == "" {
+		return DefaultAuthProxyGroupsHeader
+	}
+	return p.GroupsHeader
+}
+
+// Parse returns the networks of the trusted proxies, nil if the proxy is disabled
+func (p AuthProxy) Parse() ([]netip.Prefix, error) {
+	if !p.Enabled {
+		return nil, nil
+	}
+	if len(p.TrustedProxies) == 0 {
+		return nil, errors.New("auth proxy requires trustedProxies")
+	}
+	trustedProxies, err := parsePrefixes(p.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted auth proxy network: %w", err)
+	}
+	return trustedProxies, nil
+}
+
+// OIDC struct for logging in to the dashboard with an OpenID Connect provider. Secrets are read from files,
+// e.g. mounted from a Secret, or else from the environment
+type OIDC struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// IssuerURL is the issuer of the provider, its configuration is discovered from /.well-known/openid-configuration
+	IssuerURL string `yaml:"issuerUrl" json:"issuerUrl"`
+	ClientID  string `yaml:"clientId" json:"clientId"`
+	// ClientSecretFile is a file holding the client secret, which defaults to FORECASTLE_OIDC_CLIENT_SECRET.
+	// Public clients relying on PKCE alone have no secret
+	ClientSecretFile string `yaml:"clientSecretFile" json:"clientSecretFile,omitempty"`
+	// RedirectURL is the callback URL registered with the provider, derived from the request if empty
+	RedirectURL string `yaml:"redirectUrl" json:"redirectUrl,omitempty"`
+	// Scopes are requested in addition to openid, defaults to profile and email
+	Scopes []string `yaml:"scopes" json:"scopes,omitempty"`
+	// CookieSecretFile is a file holding the secret session cookies are encrypted with, which defaults to
+	// FORECASTLE_OIDC_COOKIE_SECRET. Without a secret, sessions don't survive restarts and aren't shared by replicas
+	CookieSecretFile string `yaml:"cookieSecretFile" json:"cookieSecretFile,omitempty"`
+	// SessionDuration is how long a login lasts, defaults to 12h
+	SessionDuration time.Duration `yaml:"sessionDuration" json:"sessionDuration,omitempty"`
+	// PostLogoutRedirectURL is where the provider sends users after logging out, the dashboard if empty
+	PostLogoutRedirectURL string `yaml:"postLogoutRedirectUrl" json:"postLogoutRedirectUrl,omitempty"`
+	// UsernameClaim is the claim naming the user, sub, email or preferred_username. Defaults to sub
+	UsernameClaim string `yaml:"usernameClaim" json:"usernameClaim,omitempty"`
+}
+
+// GetClientSecret returns the OIDC client secret
+func (o OIDC) GetClientSecret() (string, error) {
+	return readSecret(o.ClientSecretFile, OIDCClientSecretEnv)
+}
+
+// GetCookieSecret returns the secret session cookies are encrypted with, empty if none is configured
+func (o OIDC) GetCookieSecret() (string, error) {
+	return readSecret(o.CookieSecretFile, OIDCCookieSecretEnv)
+}
+
+// GetSessionDuration returns how long a login lasts
+func (o OIDC) GetSessionDuration() time.Duration {
+	if o.SessionDuration <= 0 {
+		return 12 * time.Hour
+	}
+	return o.SessionDuration
+}
+
+// Validate returns an error if the OIDC config is enabled but incomplete
+func (o OIDC) Validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	if o.IssuerURL == "" || o.ClientID == "" {
+		return errors.New("oidc login requires an issuerUrl and a clientId")
+	}
+	switch o.UsernameClaim {
+	case "", "sub", "email", "preferred_username":
+	default:
+		return fmt.Errorf("unsupported oidc usernameClaim %q, must be sub, email or preferred_username", o.UsernameClaim)
+	}
+	if _, err := o.GetClientSecret(); err != nil {
+		return err
+	}
+	_, err := o.GetCookieSecret()
+	return err
+}
+
+// readSecret reads a secret from file, or else from the environment variable env
+func readSecret(file string, env string) (string, error) {
+	if file == "" {
+		return os.Getenv(env), nil
+	}
+	secret, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret: %w", err)
+	}
+	return strings.TrimSpace(string(secret)), nil
+}
+
+// GetGroupSeparator returns the separator of nested groups
+func (c Config) GetGroupSeparator() string {
+	if c.GroupHierarchy.Separator == "" {
+		return "/"
+	}
+	return c.GroupHierarchy.Separator
+}
+
+// Deduplication struct for merging the same app discovered through multiple sources. Apps are considered
+// the same when they share an app id, or else a normalized URL
+type Deduplication struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// SourcePrecedence lists discovery sources from highest to lowest precedence when merging fields
+	SourcePrecedence []string `yaml:"sourcePrecedence" json:"sourcePrecedence"`
 }
 
 // NamespaceSelector struct for selecting namespaces based on labels and names
@@ -34,6 +444,20 @@ type NamespaceSelector struct {
 	LabelSelector *metav1.LabelSelector
 }
 
+// GetGroup returns the configuration of the group with the given slug. Keys are matched case insensitively,
+// since group slugs are lowercase
+func (c Config) GetGroup(slug string) (Group, bool) {
+	if group, ok := c.Groups[slug]; ok {
+		return group, true
+	}
+	for key, group := range c.Groups {
+		if strings.EqualFold(key, slug) {
+			return group, true
+		}
+	}
+	return Group{}, false
+}
+
 // GetConfig returns forecastle configuration
 func GetConfig() (*Config, error) {
 	var c Config
@@ -41,6 +465,18 @@ func GetConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := annotations.ValidateDialects(c.Annotations.Dialects); err != nil {
+		return nil, err
+	}
+	if _, _, err := c.ClientNetworks.Parse(); err != nil {
+		return nil, err
+	}
+	if err := c.Auth.OIDC.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := c.Auth.Proxy.Parse(); err != nil {
+		return nil, err
+	}
 
 	return &c, nil
 }
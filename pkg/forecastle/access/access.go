@@ -0,0 +1,101 @@
+package access
+
+import (
+	"strconv"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	logger = log.New()
+)
+
+// Access describes how access to an app is restricted
+type Access struct {
+	// NetworkRestricted is true if the app only accepts clients from some networks
+	NetworkRestricted bool
+	// RequiresAuth is true if the app sits behind an authentication check
+	RequiresAuth bool
+}
+
+// Merge returns the restrictions of a and b combined
+func (a Access) Merge(b Access) Access {
+	return Access{
+		NetworkRestricted: a.NetworkRestricted || b.NetworkRestricted,
+		RequiresAuth:      a.RequiresAuth || b.RequiresAuth,
+	}
+}
+
+// WithOverrides returns a with the flags replaced by the explicit values of the network restricted and requires
+// auth annotations. Empty or invalid values keep the detected flag
+func (a Access) WithOverrides(networkRestricted string, requiresAuth string) Access {
+	if value, err := strconv.ParseBool(networkRestricted); err == nil {
+		a.NetworkRestricted = value
+	}
+	if value, err := strconv.ParseBool(requiresAuth); err == nil {
+		a.RequiresAuth = value
+	}
+	return a
+}
+
+// Object is the object an app is discovered from, as seen by detectors
+type Object struct {
+	// Kind is the kind of the object, e.g. Ingress or IngressRoute
+	Kind        string
+	Namespace   string
+	Name        string
+	Annotations map[string]string
+	// Middlewares are the Traefik middlewares the routes of an IngressRoute reference
+	Middlewares []types.NamespacedName
+}
+
+// Detector detects the access restrictions of an app from the ingress controller configuration of its object
+type Detector interface {
+	Detect(object Object) Access
+}
+
+// Detectors combines detectors, an object is restricted if any of them detects a restriction
+type Detectors []Detector
+
+// Detect returns the merged restrictions detected by every detector
+func (d Detectors) Detect(object Object) Access {
+	var detected Access
+	for _, detector := range d {
+		detected = detected.Merge(detector.Detect(object))
+	}
+	return detected
+}
+
+// NewDetector returns the detectors enabled by the config, or nil if access detection is disabled
+func NewDetector(appConfig config.Config, clients kube.Clients) Detector {
+	if appConfig.AccessDetection.Disabled {
+		return nil
+	}
+
+	var detectors Detectors
+	for _, name := range appConfig.GetAccessDetectors() {
+		switch name {
+		case NginxDetectorName:
+			detectors = append(detectors, NginxDetector{})
+		case TraefikDetectorName:
+			detectors = append(detectors, NewTraefikDetector(clients.IngressRoutesClient))
+		default:
+			logger.Warnf("Ignoring unknown access detector %q", name)
+		}
+	}
+	if len(detectors) == 0 {
+		return nil
+	}
+	return detectors
+}
+
+// Detect returns the restrictions detector detects for object, or none if detector is nil
+func Detect(detector Detector, object Object) Access {
+	if detector == nil {
+		return Access{}
+	}
+	return detector.Detect(object)
+}
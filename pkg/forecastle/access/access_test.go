@@ -0,0 +1,220 @@
+package access
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	traefikv1alpha1 "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNginxDetector_Detect(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        Access
+	}{
+		{
+			name: "NoAnnotations",
+			want: Access{},
+		},
+		{
+			name:        "WhitelistSourceRange",
+			annotations: map[string]string{nginxWhitelistSourceRangeAnnotation: "10.0.0.0/8"},
+			want:        Access{NetworkRestricted: true},
+		},
+		{
+			name:        "AllowlistSourceRange",
+			annotations: map[string]string{nginxAllowlistSourceRangeAnnotation: "10.0.0.0/8,192.168.0.0/16"},
+			want:        Access{NetworkRestricted: true},
+		},
+		{
+			name:        "EmptySourceRange",
+			annotations: map[string]string{nginxAllowlistSourceRangeAnnotation: " "},
+			want:        Access{},
+		},
+		{
+			name:        "AuthURL",
+			annotations: map[string]string{nginxAuthURLAnnotation: "https://oauth2-proxy.example.com/oauth2/auth"},
+			want:        Access{RequiresAuth: true},
+		},
+		{
+			name: "BasicAuthAndSourceRange",
+			annotations: map[string]string{
+				nginxAuthTypeAnnotation:             "basic",
+				nginxWhitelistSourceRangeAnnotation: "10.0.0.0/8",
+			},
+			want: Access{NetworkRestricted: true, RequiresAuth: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (NginxDetector{}).Detect(Object{Annotations: tt.annotations}); got != tt.want {
+				t.Errorf("Detect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraefikDetector_Detect(t *testing.T) {
+	middlewares := map[types.NamespacedName]*traefikv1alpha1.Middleware{
+		{Namespace: "apps", Name: "office-only"}: {Spec: traefikv1alpha1.MiddlewareSpec{
+			IPAllowList: &dynamic.IPAllowList{SourceRange: []string{"10.0.0.0/8"}},
+		}},
+		{Namespace: "apps", Name: "legacy-whitelist"}: {Spec: traefikv1alpha1.MiddlewareSpec{
+			IPWhiteList: &dynamic.IPWhiteList{SourceRange: []string{"10.0.0.0/8"}},
+		}},
+		{Namespace: "auth", Name: "sso"}: {Spec: traefikv1alpha1.MiddlewareSpec{
+			ForwardAuth: &traefikv1alpha1.ForwardAuth{Address: "http://authelia.auth.svc/api/verify"},
+		}},
+		{Namespace: "kube-system", Name: "headers"}: {},
+	}
+	var fetched []types.NamespacedName
+	getter := func(namespace string, name string) (*traefikv1alpha1.Middleware, error) {
+		ref := types.NamespacedName{Namespace: namespace, Name: name}
+		fetched = append(fetched, ref)
+		if middleware, ok := middlewares[ref]; ok {
+			return middleware, nil
+		}
+		if namespace == "broken" {
+			return nil, errors.New("connection refused")
+		}
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "traefik.io", Resource: "middlewares"}, name)
+	}
+
+	tests := []struct {
+		name        string
+		object      Object
+		want        Access
+		wantFetched []types.NamespacedName
+	}{
+		{
+			name: "IngressRouteMiddlewares",
+			object: Object{Kind: "IngressRoute", Namespace: "apps", Middlewares: []types.NamespacedName{
+				{Namespace: "apps", Name: "office-only"},
+				{Namespace: "auth", Name: "sso"},
+			}},
+			want:        Access{NetworkRestricted: true, RequiresAuth: true},
+			wantFetched: []types.NamespacedName{{Namespace: "apps", Name: "office-only"}, {Namespace: "auth", Name: "sso"}},
+		},
+		{
+			name: "IngressRouteMiddlewaresAreCached",
+			object: Object{Kind: "IngressRoute", Namespace: "apps", Middlewares: []types.NamespacedName{
+				{Namespace: "apps", Name: "office-only"},
+			}},
+			want: Access{NetworkRestricted: true},
+		},
+		{
+			name: "IngressAnnotationInIngressNamespace",
+			object: Object{Kind: "Ingress", Namespace: "apps", Annotations: map[string]string{
+				traefikRouterMiddlewaresAnnotation: "apps-legacy-whitelist@kubernetescrd",
+			}},
+			want:        Access{NetworkRestricted: true},
+			wantFetched: []types.NamespacedName{{Namespace: "apps", Name: "legacy-whitelist"}},
+		},
+		{
+			name: "IngressAnnotationInOtherNamespace",
+			object: Object{Kind: "Ingress", Namespace: "apps", Annotations: map[string]string{
+				traefikRouterMiddlewaresAnnotation: "kube-system-headers@kubernetescrd, auth-sso@kubernetescrd,compress@file",
+			}},
+			want: Access{RequiresAuth: true},
+			wantFetched: []types.NamespacedName{
+				{Namespace: "kube", Name: "system-headers"},
+				{Namespace: "kube-system", Name: "headers"},
+			},
+		},
+		{
+			name: "MissingAndUnreachableMiddlewares",
+			object: Object{Kind: "Ingress", Namespace: "apps", Annotations: map[string]string{
+				traefikRouterMiddlewaresAnnotation: "apps-missing@kubernetescrd,broken-mw@kubernetescrd",
+			}},
+			want: Access{},
+			wantFetched: []types.NamespacedName{
+				{Namespace: "apps", Name: "missing"},
+				{Namespace: "broken", Name: "mw"},
+			},
+		},
+	}
+
+	detector := NewTraefikDetectorWithGetter(getter)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched = nil
+			if got := detector.Detect(tt.object); got != tt.want {
+				t.Errorf("Detect() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(fetched, tt.wantFetched) {
+				t.Errorf("Detect() fetched %v, want %v", fetched, tt.wantFetched)
+			}
+		})
+	}
+}
+
+func TestTraefikDetector_DetectWithoutClient(t *testing.T) {
+	object := Object{Middlewares: []types.NamespacedName{{Namespace: "apps", Name: "office-only"}}}
+	if got := NewTraefikDetector(nil).Detect(object); got != (Access{}) {
+		t.Errorf("Detect() = %+v, want no restrictions", got)
+	}
+}
+
+func TestAccess_WithOverrides(t *testing.T) {
+	detected := Access{NetworkRestricted: true, RequiresAuth: true}
+	tests := []struct {
+		name              string
+		networkRestricted string
+		requiresAuth      string
+		want              Access
+	}{
+		{
+			name: "NoOverrides",
+			want: detected,
+		},
+		{
+			name:              "ExplicitFalse",
+			networkRestricted: "false",
+			requiresAuth:      "false",
+			want:              Access{},
+		},
+		{
+			name:              "InvalidValueKeepsDetected",
+			networkRestricted: "maybe",
+			requiresAuth:      "false",
+			want:              Access{NetworkRestricted: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detected.WithOverrides(tt.networkRestricted, tt.requiresAuth); got != tt.want {
+				t.Errorf("WithOverrides() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if got := (Access{}).WithOverrides("true", "1"); got != detected {
+		t.Errorf("WithOverrides() = %+v, want %+v", got, detected)
+	}
+}
+
+func TestNewDetector(t *testing.T) {
+	if got := NewDetector(config.Config{AccessDetection: config.AccessDetection{Disabled: true}}, kube.Clients{}); got != nil {
+		t.Errorf("NewDetector() = %v, want nil when disabled", got)
+	}
+	if got := NewDetector(config.Config{AccessDetection: config.AccessDetection{Detectors: []string{"unknown"}}}, kube.Clients{}); got != nil {
+		t.Errorf("NewDetector() = %v, want nil without known detectors", got)
+	}
+
+	detector := NewDetector(config.Config{}, kube.Clients{})
+	object := Object{Annotations: map[string]string{nginxAuthURLAnnotation: "https://auth.example.com"}}
+	if got := Detect(detector, object); got != (Access{RequiresAuth: true}) {
+		t.Errorf("Detect() = %+v, want requires auth", got)
+	}
+	if got := Detect(nil, object); got != (Access{}) {
+		t.Errorf("Detect() with nil detector = %+v, want no restrictions", got)
+	}
+}
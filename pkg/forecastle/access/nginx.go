@@ -0,0 +1,31 @@
+package access
+
+import "strings"
+
+// NginxDetectorName is the name of the detector reading ingress-nginx annotations
+const NginxDetectorName = "nginx"
+
+const (
+	nginxWhitelistSourceRangeAnnotation = "nginx.ingress.kubernetes.io/whitelist-source-range"
+	nginxAllowlistSourceRangeAnnotation = "nginx.ingress.kubernetes.io/allowlist-source-range"
+	nginxAuthURLAnnotation              = "nginx.ingress.kubernetes.io/auth-url"
+	nginxAuthTypeAnnotation             = "nginx.ingress.kubernetes.io/auth-type"
+)
+
+// NginxDetector detects source range allowlists and external or basic authentication configured with
+// ingress-nginx annotations
+type NginxDetector struct{}
+
+// Detect returns the restrictions configured by the ingress-nginx annotations of object
+func (NginxDetector) Detect(object Object) Access {
+	return Access{
+		NetworkRestricted: hasValue(object.Annotations, nginxWhitelistSourceRangeAnnotation) ||
+			hasValue(object.Annotations, nginxAllowlistSourceRangeAnnotation),
+		RequiresAuth: hasValue(object.Annotations, nginxAuthURLAnnotation) ||
+			hasValue(object.Annotations, nginxAuthTypeAnnotation),
+	}
+}
+
+func hasValue(annots map[string]string, key string) bool {
+	return strings.TrimSpace(annots[key]) != ""
+}
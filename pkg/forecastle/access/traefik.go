@@ -0,0 +1,125 @@
+package access
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	ingressroutes "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/generated/clientset/versioned"
+	traefikv1alpha1 "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TraefikDetectorName is the name of the detector inspecting Traefik middlewares
+const TraefikDetectorName = "traefik"
+
+// traefikRouterMiddlewaresAnnotation lists the middlewares of an Ingress served by Traefik
+const traefikRouterMiddlewaresAnnotation = "traefik.ingress.kubernetes.io/router.middlewares"
+
+// traefikCRDProvider is the provider suffix of middlewares defined as Middleware objects
+const traefikCRDProvider = "@kubernetescrd"
+
+// MiddlewareGetter fetches a Traefik Middleware object
+type MiddlewareGetter func(namespace string, name string) (*traefikv1alpha1.Middleware, error)
+
+// TraefikDetector detects ipAllowList and authentication middlewares referenced by IngressRoutes, or by
+// Ingresses through the router.middlewares annotation. Middlewares are fetched once per detector
+type TraefikDetector struct {
+	getMiddleware MiddlewareGetter
+
+	mutex sync.Mutex
+	cache map[types.NamespacedName]*traefikv1alpha1.Middleware
+}
+
+// NewTraefikDetector returns a TraefikDetector fetching middlewares with client. Nothing is detected if client is nil
+func NewTraefikDetector(client ingressroutes.Interface) *TraefikDetector {
+	if client == nil {
+		return NewTraefikDetectorWithGetter(nil)
+	}
+	return NewTraefikDetectorWithGetter(func(namespace string, name string) (*traefikv1alpha1.Middleware, error) {
+		return client.TraefikV1alpha1().Middlewares(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	})
+}
+
+// NewTraefikDetectorWithGetter returns a TraefikDetector fetching middlewares with getMiddleware
+func NewTraefikDetectorWithGetter(getMiddleware MiddlewareGetter) *TraefikDetector {
+	return &TraefikDetector{
+		getMiddleware: getMiddleware,
+		cache:         map[types.NamespacedName]*traefikv1alpha1.Middleware{},
+	}
+}
+
+// Detect returns the restrictions of the Traefik middlewares object references
+func (td *TraefikDetector) Detect(object Object) Access {
+	if td.getMiddleware == nil {
+		return Access{}
+	}
+
+	var detected Access
+	for _, ref := range object.Middlewares {
+		detected = detected.Merge(middlewareAccess(td.middleware(ref)))
+	}
+	for _, value := range strings.Split(object.Annotations[traefikRouterMiddlewaresAnnotation], ",") {
+		value = strings.TrimSpace(value)
+		if !strings.HasSuffix(value, traefikCRDProvider) {
+			// Middlewares of other providers, e.g. @file, can't be inspected
+			continue
+		}
+		for _, ref := range annotationRefs(strings.TrimSuffix(value, traefikCRDProvider), object.Namespace) {
+			if middleware := td.middleware(ref); middleware != nil {
+				detected = detected.Merge(middlewareAccess(middleware))
+				break
+			}
+		}
+	}
+	return detected
+}
+
+// middleware returns the middleware ref points to, or nil if it can't be fetched
+func (td *TraefikDetector) middleware(ref types.NamespacedName) *traefikv1alpha1.Middleware {
+	td.mutex.Lock()
+	defer td.mutex.Unlock()
+
+	if middleware, ok := td.cache[ref]; ok {
+		return middleware
+	}
+	middleware, err := td.getMiddleware(ref.Namespace, ref.Name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Warnf("Unable to fetch Traefik middleware '%v': %v", ref, err)
+		}
+		middleware = nil
+	}
+	td.cache[ref] = middleware
+	return middleware
+}
+
+// middlewareAccess returns the restrictions middleware applies
+func middlewareAccess(middleware *traefikv1alpha1.Middleware) Access {
+	if middleware == nil {
+		return Access{}
+	}
+	spec := middleware.Spec
+	return Access{
+		NetworkRestricted: spec.IPAllowList != nil || spec.IPWhiteList != nil,
+		RequiresAuth:      spec.ForwardAuth != nil || spec.BasicAuth != nil || spec.DigestAuth != nil,
+	}
+}
+
+// annotationRefs returns the candidate references of a middleware named <namespace>-<name> in the router.middlewares
+// annotation. Both parts may contain dashes, so the namespace of the Ingress is tried first, then every split
+func annotationRefs(qualifiedName string, namespace string) []types.NamespacedName {
+	var refs []types.NamespacedName
+	if name, ok := strings.CutPrefix(qualifiedName, namespace+"-"); ok && name != "" {
+		refs = append(refs, types.NamespacedName{Namespace: namespace, Name: name})
+	}
+	for i := range len(qualifiedName) {
+		if qualifiedName[i] != '-' || i == 0 || i == len(qualifiedName)-1 || qualifiedName[:i] == namespace {
+			continue
+		}
+		refs = append(refs, types.NamespacedName{Namespace: qualifiedName[:i], Name: qualifiedName[i+1:]})
+	}
+	return refs
+}
@@ -1,15 +1,23 @@
 package crdapps
 
 import (
+	"maps"
+	"net/url"
 	"strings"
 
-	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/derivation"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle/filters"
 	"github.com/stakater/Forecastle/v1/pkg/kube"
 	"github.com/stakater/Forecastle/v1/pkg/kube/lists/forecastleapps"
+	"github.com/stakater/Forecastle/v1/pkg/kube/wrappers"
 	"github.com/stakater/Forecastle/v1/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -18,10 +26,14 @@ var (
 
 // List struct is used for listing forecastle apps
 type List struct {
-	appConfig config.Config
-	err       error // Used for forwarding errors
-	items     []forecastle.App
-	clients   kube.Clients
+	appConfig  config.Config
+	err        error // Used for forwarding errors
+	items      []forecastle.App
+	clients    kube.Clients
+	namespaces map[string]*corev1.Namespace
+	// statusUpdates enables writing the discovery results back to the status of the forecastleApps
+	statusUpdates bool
+	detector      access.Detector
 }
 
 // NewList func creates a new instance of apps lister
@@ -32,19 +44,42 @@ func NewList(clients kube.Clients, appConfig config.Config) *List {
 	}
 }
 
+// WithNamespaces function sets the namespace objects whose forecastle annotations and labels act as defaults
+func (al *List) WithNamespaces(namespaces map[string]*corev1.Namespace) *List {
+	al.namespaces = namespaces
+	return al
+}
+
+// WithStatusUpdates function enables writing the discovery results back to the status subresource of the forecastleApps
+func (al *List) WithStatusUpdates(enabled bool) *List {
+	al.statusUpdates = enabled
+	return al
+}
+
+// WithAccessDetector function sets the detector of access restrictions from the Ingresses and IngressRoutes
+// the URLs of forecastleApps are discovered from
+func (al *List) WithAccessDetector(detector access.Detector) *List {
+	al.detector = detector
+	return al
+}
+
 // Populate function that populates a list of forecastle apps from forecastleapps in selected namespaces
 func (al *List) Populate(namespaces ...string) *List {
 	forecastleAppListObj := forecastleapps.NewList(al.clients.ForecastleAppsClient, al.appConfig).
 		Populate(namespaces...)
 
-	var forecastleAppList []v1alpha1.ForecastleApp
+	var forecastleAppList []v1beta1.ForecastleApp
 	var err error
 
 	// Apply Instance filter
 	if len(al.appConfig.InstanceName) != 0 {
 		forecastleAppList, err = forecastleAppListObj.
-			Filter(func(app v1alpha1.ForecastleApp, cfg config.Config) bool {
-				return filters.ByInstance(app.Spec.Instance, cfg)
+			Filter(func(app v1beta1.ForecastleApp, cfg config.Config) bool {
+				instance := app.Spec.Instance
+				if instance == "" {
+					instance = wrappers.NewNamespaceWrapper(al.namespaces[app.Namespace]).GetAnnotationValue(annotations.ForecastleInstanceAnnotation)
+				}
+				return filters.ByInstance(instance, cfg)
 			}).
 			Get()
 	} else {
@@ -55,7 +90,12 @@ func (al *List) Populate(namespaces ...string) *List {
 		al.err = err
 	}
 
-	al.items, al.err = convertForecastleAppCustomResourcesToForecastleApps(al.clients, forecastleAppList)
+	var outdated []v1beta1.ForecastleApp
+	al.items, outdated, al.err = convertForecastleAppCustomResourcesToForecastleApps(al.clients, al.appConfig, forecastleAppList, al.namespaces, al.detector)
+
+	if al.statusUpdates {
+		updateStatuses(al.clients.ForecastleAppsClient, outdated)
+	}
 
 	return al
 }
@@ -65,11 +105,25 @@ func (al *List) Get() ([]forecastle.App, error) {
 	return al.items, al.err
 }
 
-func convertForecastleAppCustomResourcesToForecastleApps(clients kube.Clients, forecastleApps []v1alpha1.ForecastleApp) (apps []forecastle.App, err error) {
+// convertForecastleAppCustomResourcesToForecastleApps converts forecastleApps to apps. It also returns copies of the
+// forecastleApps whose status is outdated, carrying their new status
+func convertForecastleAppCustomResourcesToForecastleApps(clients kube.Clients, appConfig config.Config, forecastleApps []v1beta1.ForecastleApp, namespaces map[string]*corev1.Namespace,
+	detector access.Detector,
+) (
+	apps []forecastle.App, outdated []v1beta1.ForecastleApp, err error,
+) {
+	now := metav1.Now()
+	rules := derivation.NewRules(appConfig.Derivation)
 	for _, forecastleApp := range forecastleApps {
 		logger.Infof("Found forecastleApp with Name '%v' in Namespace '%v'", forecastleApp.Name, forecastleApp.Namespace)
 
-		url, err := getURL(clients, forecastleApp)
+		url, source, err := getURL(clients, appConfig, forecastleApp)
+
+		if status := newStatus(forecastleApp, url, err, now); statusNeedsUpdate(forecastleApp.Status, status) {
+			updated := forecastleApp.DeepCopy()
+			updated.Status = status
+			outdated = append(outdated, *updated)
+		}
 
 		if err != nil {
 			logger.Errorf("Skipping... Error fetching URL for forecastleApp with Name '%v' in Namespace '%v'. Error: %v",
@@ -77,15 +131,142 @@ func convertForecastleAppCustomResourcesToForecastleApps(clients kube.Clients, f
 			continue
 		}
 
+		namespace := wrappers.NewNamespaceWrapper(namespaces[forecastleApp.Namespace])
+		metadata := newMetadata(forecastleApp, namespace, url)
+
+		var recommendedLabels map[string]string
+		if appConfig.RecommendedLabels {
+			recommendedLabels = forecastleApp.Labels
+		}
+
+		name := forecastleApp.Spec.Name
+		if name == "" {
+			name = recommendedLabels[annotations.AppNameLabel]
+		}
+		if name == "" {
+			name = rules.Name(metadata)
+		}
+		if name == "" {
+			name = forecastleApp.Name
+		}
+
+		group := forecastleApp.Spec.Group
+		if group == "" {
+			group = recommendedLabels[annotations.AppPartOfLabel]
+		}
+		if group == "" {
+			group = rules.Group(metadata)
+		}
+		if group == "" {
+			group = namespace.GetAnnotationValue(annotations.ForecastleGroupAnnotation)
+		}
+		groupFromNamespace := group == ""
+		if groupFromNamespace {
+			group = namespace.GetDisplayName()
+		}
+
+		icon := forecastleApp.Spec.Icon
+		if icon == "" {
+			icon = namespace.GetAnnotationValue(annotations.ForecastleIconAnnotation)
+		}
+
+		var detected access.Access
+		if source != nil {
+			detected = access.Detect(detector, *source)
+		}
+		detected = detected.WithOverrides(
+			namespace.GetAnnotationValue(annotations.ForecastleNetworkRestrictedAnnotation),
+			namespace.GetAnnotationValue(annotations.ForecastleRequiresAuthAnnotation),
+		)
+		if forecastleApp.Spec.NetworkRestricted != nil {
+			detected.NetworkRestricted = *forecastleApp.Spec.NetworkRestricted
+		}
+
+		allowedGroups := forecastleApp.Spec.AllowedGroups
+		if len(allowedGroups) == 0 {
+			allowedGroups = namespace.GetAllowedGroups()
+		}
+
+		var properties map[string]string
+		if namespaceProperties := namespace.GetProperties(); len(namespaceProperties) != 0 {
+			properties = maps.Clone(namespaceProperties)
+		}
+		for key, value := range forecastleApp.Spec.Properties {
+			if properties == nil {
+				properties = map[string]string{}
+			}
+			properties[key] = value
+		}
+
+		var propertyTypes map[string]string
+		for key, propertyType := range forecastleApp.Spec.PropertyTypes {
+			if _, ok := forecastleApp.Spec.Properties[key]; !ok {
+				continue
+			}
+			if propertyTypes == nil {
+				propertyTypes = map[string]string{}
+			}
+			propertyTypes[key] = string(propertyType)
+		}
+
 		apps = append(apps, forecastle.App{
-			Name:              forecastleApp.Spec.Name,
-			Group:             strings.ToLower(forecastleApp.Spec.Group),
-			Icon:              forecastleApp.Spec.Icon,
-			URL:               url,
-			DiscoverySource:   forecastle.ForecastleAppCRD,
-			NetworkRestricted: forecastleApp.Spec.NetworkRestricted,
-			Properties:        forecastleApp.Spec.Properties,
+			Name:               name,
+			Group:              strings.ToLower(group),
+			GroupDisplayName:   group,
+			Icon:               icon,
+			URL:                url,
+			Description:        forecastleApp.Spec.Description,
+			Tags:               forecastleApp.Spec.Tags,
+			Weight:             int(forecastleApp.Spec.Weight),
+			Links:              convertLinks(forecastleApp),
+			Version:            recommendedLabels[annotations.AppVersionLabel],
+			Component:          recommendedLabels[annotations.AppComponentLabel],
+			ManagedBy:          recommendedLabels[annotations.AppManagedByLabel],
+			AppID:              forecastleApp.Spec.AppID,
+			DiscoverySource:    forecastle.ForecastleAppCRD,
+			NetworkRestricted:  detected.NetworkRestricted,
+			RequiresAuth:       detected.RequiresAuth,
+			AllowedGroups:      allowedGroups,
+			Properties:         properties,
+			PropertyTypes:      propertyTypes,
+			Origin:             forecastle.NewOrigin("ForecastleApp", forecastleApp.ObjectMeta),
+			Annotations:        annotations.ForecastleAnnotations(forecastleApp.Annotations),
+			GroupFromNamespace: groupFromNamespace,
 		})
 	}
 	return
 }
+
+// newMetadata returns the metadata derivation rules are evaluated against for forecastleApp
+func newMetadata(forecastleApp v1beta1.ForecastleApp, namespace *wrappers.NamespaceWrapper, appURL string) derivation.Metadata {
+	namespaceLabels, namespaceAnnotations := namespace.GetMetadata()
+	metadata := derivation.Metadata{
+		Kind:                 "ForecastleApp",
+		Name:                 forecastleApp.Name,
+		Namespace:            forecastleApp.Namespace,
+		Labels:               forecastleApp.Labels,
+		Annotations:          forecastleApp.Annotations,
+		NamespaceLabels:      namespaceLabels,
+		NamespaceAnnotations: namespaceAnnotations,
+	}
+	if parsedURL, err := url.Parse(appURL); err == nil {
+		metadata.Host = parsedURL.Hostname()
+	}
+	return metadata
+}
+
+// convertLinks converts the links of forecastleApp, skipping links without a valid URL
+func convertLinks(forecastleApp v1beta1.ForecastleApp) []forecastle.Link {
+	if len(forecastleApp.Spec.Links) == 0 {
+		return nil
+	}
+	links := make([]forecastle.Link, 0, len(forecastleApp.Spec.Links))
+	for _, link := range forecastleApp.Spec.Links {
+		links = append(links, forecastle.Link{Label: link.Label, URL: link.URL, Icon: link.Icon})
+	}
+	valid, errs := wrappers.ValidLinks(links)
+	for _, err := range errs {
+		logger.Warnf("Skipping link of forecastleApp '%v/%v': %v", forecastleApp.Namespace, forecastleApp.Name, err)
+	}
+	return valid
+}
@@ -1,18 +1,22 @@
 package crdapps
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
 	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
-	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
 	"github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/stakater/Forecastle/v1/pkg/kube"
 
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
 	"github.com/stakater/Forecastle/v1/pkg/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -65,7 +69,7 @@ func TestList_Populate(t *testing.T) {
 
 	forecastleApp := testutil.CreateForecastleApp("app-1", "https://google.com", "default", "https://google.com/icon.png")
 
-	_, _ = clients.ForecastleAppsClient.ForecastleV1alpha1().ForecastleApps("default").Create(forecastleApp)
+	_, _ = clients.ForecastleAppsClient.ForecastleV1beta1().ForecastleApps("default").Create(forecastleApp)
 
 	type args struct {
 		namespaces []string
@@ -88,11 +92,13 @@ func TestList_Populate(t *testing.T) {
 				clients: clients,
 				items: []forecastle.App{
 					{
-						Name:            "app-1",
-						Group:           "default",
-						URL:             "https://google.com",
-						Icon:            "https://google.com/icon.png",
-						DiscoverySource: forecastle.ForecastleAppCRD,
+						Name:             "app-1",
+						Group:            "default",
+						GroupDisplayName: "default",
+						URL:              "https://google.com",
+						Icon:             "https://google.com/icon.png",
+						DiscoverySource:  forecastle.ForecastleAppCRD,
+						Origin:           &forecastle.Origin{Kind: "ForecastleApp", Namespace: "default", Name: "app-1"},
 					},
 				},
 			},
@@ -106,7 +112,7 @@ func TestList_Populate(t *testing.T) {
 		})
 	}
 
-	_ = clients.ForecastleAppsClient.ForecastleV1alpha1().ForecastleApps("default").Delete("app-1", &metav1.DeleteOptions{})
+	_ = clients.ForecastleAppsClient.ForecastleV1beta1().ForecastleApps("default").Delete("app-1", &metav1.DeleteOptions{})
 
 }
 
@@ -177,7 +183,7 @@ func Test_convertForecastleAppCustomResourcesToForecastleApps(t *testing.T) {
 	}
 
 	type args struct {
-		forecastleApps []v1alpha1.ForecastleApp
+		forecastleApps []v1beta1.ForecastleApp
 	}
 	tests := []struct {
 		name     string
@@ -188,14 +194,14 @@ func Test_convertForecastleAppCustomResourcesToForecastleApps(t *testing.T) {
 		{
 			name: "TestConvertForecastleAppCustomResourcesToForecastleAppsWithNoApps",
 			args: args{
-				forecastleApps: []v1alpha1.ForecastleApp{},
+				forecastleApps: []v1beta1.ForecastleApp{},
 			},
 			wantApps: nil,
 		},
 		{
 			name: "TestConvertForecastleAppCustomResourcesToForecastleApps",
 			args: args{
-				forecastleApps: []v1alpha1.ForecastleApp{
+				forecastleApps: []v1beta1.ForecastleApp{
 					*testutil.CreateForecastleApp("app1", "https://google.com", "default", "https://google.com/icon.png"),
 				},
 			},
@@ -212,7 +218,7 @@ func Test_convertForecastleAppCustomResourcesToForecastleApps(t *testing.T) {
 		{
 			name: "TestConvertForecastleAppCustomResourcesToForecastleAppsWithInvalidAppRouteRef",
 			args: args{
-				forecastleApps: []v1alpha1.ForecastleApp{
+				forecastleApps: []v1beta1.ForecastleApp{
 					*testutil.CreateForecastleApp("app1", "https://google.com", "default", "https://google.com/icon.png"),
 					*testutil.CreateForecastleAppWithURLFromRoute("invalid-app", "default", "https://google.com/icon.png", "invalid-route"),
 				},
@@ -230,10 +236,204 @@ func Test_convertForecastleAppCustomResourcesToForecastleApps(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotApps, err := convertForecastleAppCustomResourcesToForecastleApps(clients, tt.args.forecastleApps); !reflect.DeepEqual(gotApps, tt.wantApps) && err != tt.err {
+			if gotApps, _, err := convertForecastleAppCustomResourcesToForecastleApps(clients, config.Config{}, tt.args.forecastleApps, nil, nil); !reflect.DeepEqual(gotApps, tt.wantApps) && err != tt.err {
 				t.Errorf("convertForecastleAppCustomResourcesToForecastleApps() = %v, want %v, err = %v, wantErr = %v", gotApps, tt.wantApps, err, tt.err)
 			}
 		})
 	}
 
 }
+
+func Test_convertForecastleAppCustomResourcesToForecastleApps_Derivation(t *testing.T) {
+	clients := kube.Clients{
+		ForecastleAppsClient: fake.NewSimpleClientset(),
+		KubernetesClient:     kubefake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+	}
+	appConfig := config.Config{Derivation: config.Derivation{
+		Group: []string{`{{ index .Labels "app.kubernetes.io/part-of" }}`},
+	}}
+
+	derived := testutil.CreateForecastleApp("app1", "https://app1.example.com", "", "")
+	derived.Namespace = "default"
+	derived.Labels = map[string]string{"app.kubernetes.io/part-of": "Platform"}
+	explicit := testutil.CreateForecastleApp("app2", "https://app2.example.com", "Tools", "")
+	explicit.Namespace = "default"
+	explicit.Labels = derived.Labels
+	fallback := testutil.CreateForecastleApp("app3", "https://app3.example.com", "", "")
+	fallback.Namespace = "default"
+	namespaces := map[string]*corev1.Namespace{"default": {ObjectMeta: metav1.ObjectMeta{Name: "default"}}}
+
+	apps, _, err := convertForecastleAppCustomResourcesToForecastleApps(clients, appConfig, []v1beta1.ForecastleApp{*derived, *explicit, *fallback}, namespaces, nil)
+	if err != nil {
+		t.Fatalf("convertForecastleAppCustomResourcesToForecastleApps() error = %v", err)
+	}
+
+	want := []struct {
+		group              string
+		groupFromNamespace bool
+	}{
+		{"platform", false},
+		{"tools", false},
+		{"default", true},
+	}
+	if len(apps) != len(want) {
+		t.Fatalf("Expected %d apps, got %d", len(want), len(apps))
+	}
+	for i, w := range want {
+		if apps[i].Group != w.group || apps[i].GroupFromNamespace != w.groupFromNamespace {
+			t.Errorf("Expected app %d in group %q (from namespace %v), got %q (%v)", i, w.group, w.groupFromNamespace, apps[i].Group, apps[i].GroupFromNamespace)
+		}
+	}
+}
+
+func Test_convertForecastleAppCustomResourcesToForecastleApps_RecommendedLabels(t *testing.T) {
+	clients := kube.Clients{
+		ForecastleAppsClient: fake.NewSimpleClientset(),
+		KubernetesClient:     kubefake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+	}
+
+	forecastleApp := testutil.CreateForecastleApp("app1", "https://app1.example.com", "", "")
+	forecastleApp.Namespace = "default"
+	forecastleApp.Labels = map[string]string{
+		"app.kubernetes.io/part-of":    "Platform",
+		"app.kubernetes.io/version":    "v1.2.3",
+		"app.kubernetes.io/component":  "api",
+		"app.kubernetes.io/managed-by": "Helm",
+	}
+
+	apps, _, err := convertForecastleAppCustomResourcesToForecastleApps(clients, config.Config{RecommendedLabels: true}, []v1beta1.ForecastleApp{*forecastleApp}, nil, nil)
+	if err != nil {
+		t.Fatalf("convertForecastleAppCustomResourcesToForecastleApps() error = %v", err)
+	}
+	if len(apps) != 1 {
+		t.Fatalf("Expected 1 app, got %d", len(apps))
+	}
+	app := apps[0]
+	if app.Name != "app1" || app.Group != "platform" || app.Version != "v1.2.3" || app.Component != "api" || app.ManagedBy != "Helm" {
+		t.Errorf("Expected app1 in group platform with version v1.2.3, component api and managed by Helm, got %+v", app)
+	}
+}
+
+func Test_convertForecastleAppCustomResourcesToForecastleApps_NamespaceDefaultGroup(t *testing.T) {
+	clients := kube.Clients{
+		ForecastleAppsClient: fake.NewSimpleClientset(),
+		KubernetesClient:     kubefake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+	}
+	appConfig := config.Config{
+		RecommendedLabels: true,
+		Derivation:        config.Derivation{Group: []string{`{{ index .Labels "tier" }}`}},
+	}
+	namespaces := map[string]*corev1.Namespace{"default": {ObjectMeta: metav1.ObjectMeta{
+		Name:        "default",
+		Annotations: map[string]string{annotations.ForecastleGroupAnnotation: "Team Default"},
+	}}}
+
+	partOf := testutil.CreateForecastleApp("app1", "https://app1.example.com", "", "")
+	partOf.Labels = map[string]string{annotations.AppPartOfLabel: "Platform"}
+	derived := testutil.CreateForecastleApp("app2", "https://app2.example.com", "", "")
+	derived.Labels = map[string]string{"tier": "Backend"}
+	defaulted := testutil.CreateForecastleApp("app3", "https://app3.example.com", "", "")
+	forecastleApps := []v1beta1.ForecastleApp{*partOf, *derived, *defaulted}
+	for i := range forecastleApps {
+		forecastleApps[i].Namespace = "default"
+	}
+
+	apps, _, err := convertForecastleAppCustomResourcesToForecastleApps(clients, appConfig, forecastleApps, namespaces, nil)
+	if err != nil {
+		t.Fatalf("convertForecastleAppCustomResourcesToForecastleApps() error = %v", err)
+	}
+	var got []string
+	for _, app := range apps {
+		got = append(got, app.GroupDisplayName)
+	}
+	if want := []string{"Platform", "Backend", "Team Default"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Groups = %v, want %v", got, want)
+	}
+}
+
+func Test_convertForecastleAppCustomResourcesToForecastleApps_PropertyTypes(t *testing.T) {
+	clients := kube.Clients{
+		ForecastleAppsClient: fake.NewSimpleClientset(),
+		KubernetesClient:     kubefake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+	}
+
+	forecastleApp := testutil.CreateForecastleApp("app1", "https://app1.example.com", "", "")
+	forecastleApp.Namespace = "default"
+	forecastleApp.Spec.Properties = map[string]string{"Dashboard": "https://grafana/d/app", "Owner": "ops"}
+	forecastleApp.Spec.PropertyTypes = map[string]v1beta1.PropertyType{
+		"Dashboard": v1beta1.PropertyTypeURL,
+		"Replicas":  v1beta1.PropertyTypeNumber,
+	}
+
+	apps, _, err := convertForecastleAppCustomResourcesToForecastleApps(clients, config.Config{}, []v1beta1.ForecastleApp{*forecastleApp}, nil, nil)
+	if err != nil {
+		t.Fatalf("convertForecastleAppCustomResourcesToForecastleApps() error = %v", err)
+	}
+	if len(apps) != 1 {
+		t.Fatalf("Expected 1 app, got %d", len(apps))
+	}
+	body, err := json.Marshal(apps[0])
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var got struct {
+		PropertyTypes map[string]string `json:"propertyTypes"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if want := map[string]string{"Dashboard": "url"}; !reflect.DeepEqual(got.PropertyTypes, want) {
+		t.Errorf("propertyTypes = %v, want %v", got.PropertyTypes, want)
+	}
+}
+
+func Test_convertForecastleAppCustomResourcesToForecastleApps_AccessDetection(t *testing.T) {
+	ingress := testutil.AddAnnotationToIngress(testutil.CreateIngressWithHost("app-ingress", "app.example.com"),
+		"nginx.ingress.kubernetes.io/allowlist-source-range", "10.0.0.0/8")
+	ingress.Namespace = "default"
+	clients := kube.Clients{
+		ForecastleAppsClient: fake.NewSimpleClientset(),
+		KubernetesClient:     kubefake.NewSimpleClientset(ingress), //nolint:staticcheck // NewClientset requires generated apply configurations
+	}
+
+	fromIngress := testutil.CreateForecastleAppWithURLFromIngress("app1", "", "", "app-ingress")
+	fromIngress.Namespace = "default"
+	withURL := testutil.CreateForecastleApp("app2", "https://app2.example.com", "", "")
+	withURL.Namespace = "default"
+	overridden := testutil.CreateForecastleAppWithURLFromIngress("app3", "", "", "app-ingress")
+	overridden.Namespace = "default"
+	networkRestricted := false
+	overridden.Spec.NetworkRestricted = &networkRestricted
+	namespaces := map[string]*corev1.Namespace{"default": {ObjectMeta: metav1.ObjectMeta{
+		Name:        "default",
+		Annotations: map[string]string{annotations.ForecastleRequiresAuthAnnotation: "true"},
+	}}}
+
+	apps, _, err := convertForecastleAppCustomResourcesToForecastleApps(clients, config.Config{}, []v1beta1.ForecastleApp{*fromIngress, *withURL, *overridden},
+		namespaces, access.NginxDetector{})
+	if err != nil {
+		t.Fatalf("convertForecastleAppCustomResourcesToForecastleApps() error = %v", err)
+	}
+	if len(apps) != 3 {
+		t.Fatalf("Expected 3 apps, got %d", len(apps))
+	}
+	if !apps[0].NetworkRestricted || !apps[0].RequiresAuth {
+		t.Errorf("Expected app1 to be network restricted and require auth, got %+v", apps[0])
+	}
+	if apps[1].NetworkRestricted || !apps[1].RequiresAuth {
+		t.Errorf("Expected app2 to only require auth, got %+v", apps[1])
+	}
+	if apps[2].NetworkRestricted || !apps[2].RequiresAuth {
+		t.Errorf("Expected spec.networkRestricted to override detection of app3, got %+v", apps[2])
+	}
+
+	gets := 0
+	for _, action := range clients.KubernetesClient.(*kubefake.Clientset).Actions() {
+		if action.Matches("get", "ingresses") {
+			gets++
+		}
+	}
+	if gets != 2 {
+		t.Errorf("Expected the Ingress to be fetched once per app, got %d gets", gets)
+	}
+}
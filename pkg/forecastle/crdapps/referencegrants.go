@@ -0,0 +1,84 @@
+package crdapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+)
+
+// Kinds of the objects a ForecastleApp can reference, as used in ReferenceGrants
+var (
+	forecastleAppGroupKind = schema.GroupKind{Group: v1beta1.SchemeGroupVersion.Group, Kind: "ForecastleApp"}
+	ingressGroupKind       = schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}
+	routeGroupKind         = schema.GroupKind{Group: "route.openshift.io", Kind: "Route"}
+	ingressRouteGroupKind  = schema.GroupKind{Group: "traefik.io", Kind: "IngressRoute"}
+	httpRouteGroupKind     = schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}
+	serviceGroupKind       = schema.GroupKind{Group: "", Kind: "Service"}
+)
+
+// resolveRefNamespace returns the namespace of a reference made by forecastleApp. References to another namespace
+// must be allowed by a ReferenceGrant in that namespace
+func resolveRefNamespace(gatewayClient gateway.Interface, forecastleApp v1beta1.ForecastleApp, groupKind schema.GroupKind, ref v1beta1.ObjectReference) (
+	string, error,
+) {
+	if ref.Namespace == "" || ref.Namespace == forecastleApp.Namespace {
+		return forecastleApp.Namespace, nil
+	}
+
+	allowed, err := referenceAllowed(gatewayClient, forecastleApp.Namespace, groupKind, ref.Namespace, ref.Name)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		logger.Warnf("%v '%v/%v' referenced by forecastleApp '%v/%v' is not allowed by a ReferenceGrant",
+			groupKind.Kind, ref.Namespace, ref.Name, forecastleApp.Namespace, forecastleApp.Name)
+		return "", &urlError{
+			reason: v1beta1.ReasonRefNotPermitted,
+			err: fmt.Errorf("reference to %v %v/%v is not allowed by a ReferenceGrant in namespace %v",
+				groupKind.Kind, ref.Namespace, ref.Name, ref.Namespace),
+		}
+	}
+	return ref.Namespace, nil
+}
+
+// referenceAllowed reports whether a ReferenceGrant in toNamespace allows ForecastleApps in fromNamespace to
+// reference the object of groupKind named name
+func referenceAllowed(gatewayClient gateway.Interface, fromNamespace string, groupKind schema.GroupKind, toNamespace string, name string) (bool, error) {
+	if gatewayClient == nil {
+		return false, &urlError{
+			reason: v1beta1.ReasonRefNotPermitted,
+			err:    errors.New("cross namespace references require the Gateway API ReferenceGrant resource"),
+		}
+	}
+
+	referenceGrants, err := gatewayClient.GatewayV1beta1().ReferenceGrants(toNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, referenceGrant := range referenceGrants.Items {
+		fromAllowed := false
+		for _, from := range referenceGrant.Spec.From {
+			if string(from.Group) == forecastleAppGroupKind.Group && string(from.Kind) == forecastleAppGroupKind.Kind &&
+				string(from.Namespace) == fromNamespace {
+				fromAllowed = true
+				break
+			}
+		}
+		if !fromAllowed {
+			continue
+		}
+
+		for _, to := range referenceGrant.Spec.To {
+			if string(to.Group) == groupKind.Group && string(to.Kind) == groupKind.Kind && (to.Name == nil || string(*to.Name) == name) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
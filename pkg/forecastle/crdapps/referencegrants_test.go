@@ -0,0 +1,129 @@
+package crdapps
+
+import (
+	"context"
+	"testing"
+
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+)
+
+func createReferenceGrant(namespace string, fromNamespace string, toName *string) *gatewayv1beta1.ReferenceGrant {
+	to := gatewayv1beta1.ReferenceGrantTo{Group: "networking.k8s.io", Kind: "Ingress"}
+	if toName != nil {
+		name := gatewayv1beta1.ObjectName(*toName)
+		to.Name = &name
+	}
+	return &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-portal", Namespace: namespace},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{Group: "forecastle.stakater.com", Kind: "ForecastleApp", Namespace: gatewayv1beta1.Namespace(fromNamespace)},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{to},
+		},
+	}
+}
+
+func Test_discoverURLFromRefsAcrossNamespaces(t *testing.T) {
+	otherName := "other-ingress"
+	appIngressName := "app-ingress"
+
+	tests := []struct {
+		name            string
+		referenceGrant  *gatewayv1beta1.ReferenceGrant
+		noGatewayClient bool
+		refNamespace    string
+		want            string
+		wantReason      string
+	}{
+		{
+			name:         "TestSameNamespaceNeedsNoGrant",
+			refNamespace: "portal",
+			want:         "http://portal.example.com",
+		},
+		{
+			name:         "TestCrossNamespaceWithoutGrant",
+			refNamespace: "team",
+			wantReason:   v1beta1.ReasonRefNotPermitted,
+		},
+		{
+			name:           "TestCrossNamespaceWithGrant",
+			referenceGrant: createReferenceGrant("team", "portal", nil),
+			refNamespace:   "team",
+			want:           "http://team.example.com",
+		},
+		{
+			name:           "TestCrossNamespaceWithGrantForIngress",
+			referenceGrant: createReferenceGrant("team", "portal", &appIngressName),
+			refNamespace:   "team",
+			want:           "http://team.example.com",
+		},
+		{
+			name:           "TestCrossNamespaceWithGrantForOtherIngress",
+			referenceGrant: createReferenceGrant("team", "portal", &otherName),
+			refNamespace:   "team",
+			wantReason:     v1beta1.ReasonRefNotPermitted,
+		},
+		{
+			name:           "TestCrossNamespaceWithGrantForOtherNamespace",
+			referenceGrant: createReferenceGrant("team", "elsewhere", nil),
+			refNamespace:   "team",
+			wantReason:     v1beta1.ReasonRefNotPermitted,
+		},
+		{
+			name:            "TestCrossNamespaceWithoutGatewayAPI",
+			noGatewayClient: true,
+			refNamespace:    "team",
+			wantReason:      v1beta1.ReasonRefNotPermitted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+			for namespace, host := range map[string]string{"portal": "portal.example.com", "team": "team.example.com"} {
+				ingress := testutil.CreateIngressWithHost(appIngressName, host)
+				ingress.Namespace = namespace
+				if _, err := kubeClient.NetworkingV1().Ingresses(namespace).Create(context.TODO(), ingress, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("Creating ingress failed: %v", err)
+				}
+			}
+
+			clients := kube.Clients{KubernetesClient: kubeClient}
+			if !tt.noGatewayClient {
+				gatewayClient := gatewayfake.NewSimpleClientset()
+				if tt.referenceGrant != nil {
+					_, err := gatewayClient.GatewayV1beta1().ReferenceGrants(tt.referenceGrant.Namespace).Create(context.TODO(), tt.referenceGrant, metav1.CreateOptions{})
+					if err != nil {
+						t.Fatalf("Creating ReferenceGrant failed: %v", err)
+					}
+				}
+				clients.GatewayClient = gatewayClient
+			}
+
+			forecastleApp := testutil.CreateForecastleAppWithURLFromIngress("app", "default", "https://icon", appIngressName)
+			forecastleApp.Namespace = "portal"
+			forecastleApp.Spec.URLFrom.IngressRef.Namespace = tt.refNamespace
+
+			got, _, err := discoverURLFromRefs(clients, config.Config{}, *forecastleApp)
+			if tt.wantReason != "" {
+				if err == nil || reasonForError(err) != tt.wantReason {
+					t.Fatalf("discoverURLFromRefs() error = %v, want reason %v", err, tt.wantReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("discoverURLFromRefs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("discoverURLFromRefs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
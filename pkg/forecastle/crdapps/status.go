@@ -0,0 +1,82 @@
+package crdapps
+
+import (
+	"time"
+
+	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	forecastlev1alpha1 "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// statusRefreshInterval bounds how often a new lastDiscoveredTime alone causes a status update, so that
+// unchanged apps aren't written on every cache refresh
+const statusRefreshInterval = 5 * time.Minute
+
+// newStatus computes the status of a forecastleApp from the result of discovering its URL
+func newStatus(forecastleApp v1beta1.ForecastleApp, url string, err error, now metav1.Time) v1beta1.ForecastleAppStatus {
+	status := *forecastleApp.Status.DeepCopy()
+	status.ObservedGeneration = forecastleApp.Generation
+
+	condition := metav1.Condition{
+		Type:               v1beta1.ForecastleAppConditionReady,
+		ObservedGeneration: forecastleApp.Generation,
+	}
+
+	switch {
+	case err != nil:
+		status.ResolvedURL = ""
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonForError(err)
+		condition.Message = err.Error()
+	case url == "":
+		status.ResolvedURL = ""
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1beta1.ReasonURLNotResolvable
+		condition.Message = "The referenced object does not expose a URL"
+	default:
+		status.ResolvedURL = url
+		status.LastDiscoveredTime = &now
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = v1beta1.ReasonURLResolved
+		condition.Message = "URL resolved"
+	}
+
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return status
+}
+
+// statusNeedsUpdate reports whether the desired status differs from the current one enough to be written
+func statusNeedsUpdate(current v1beta1.ForecastleAppStatus, desired v1beta1.ForecastleAppStatus) bool {
+	if current.ResolvedURL != desired.ResolvedURL || current.ObservedGeneration != desired.ObservedGeneration {
+		return true
+	}
+	if !equality.Semantic.DeepEqual(current.Conditions, desired.Conditions) {
+		return true
+	}
+	if desired.LastDiscoveredTime == nil {
+		return false
+	}
+	return current.LastDiscoveredTime == nil || desired.LastDiscoveredTime.Sub(current.LastDiscoveredTime.Time) >= statusRefreshInterval
+}
+
+// updateStatuses writes the status of forecastleApps to their status subresource, through v1alpha1 on clusters
+// whose ForecastleApp CRD doesn't serve v1beta1 yet
+func updateStatuses(client forecastlev1alpha1.Interface, forecastleApps []v1beta1.ForecastleApp) {
+	for i := range forecastleApps {
+		forecastleApp := &forecastleApps[i]
+		_, err := client.ForecastleV1beta1().ForecastleApps(forecastleApp.Namespace).UpdateStatus(forecastleApp)
+		if apierrors.IsNotFound(err) {
+			converted := &v1alpha1.ForecastleApp{}
+			converted.ConvertFrom(forecastleApp)
+			_, err = client.ForecastleV1alpha1().ForecastleApps(forecastleApp.Namespace).UpdateStatus(converted)
+		}
+		if err != nil {
+			logger.Warnf("Error updating status of forecastleApp with Name '%v' in Namespace '%v'. Error: %v",
+				forecastleApp.Name, forecastleApp.Namespace, err)
+		}
+	}
+}
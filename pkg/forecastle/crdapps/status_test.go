@@ -0,0 +1,165 @@
+package crdapps
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	"github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/fake"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/kube"
+	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_newStatus(t *testing.T) {
+	now := metav1.Now()
+	app := *testutil.CreateForecastleApp("app1", "", "default", "https://icon")
+	app.Generation = 3
+
+	tests := []struct {
+		name           string
+		url            string
+		err            error
+		wantURL        string
+		wantStatus     metav1.ConditionStatus
+		wantReason     string
+		wantDiscovered bool
+	}{
+		{
+			name:           "TestNewStatusWithResolvedURL",
+			url:            "https://app.example.com",
+			wantURL:        "https://app.example.com",
+			wantStatus:     metav1.ConditionTrue,
+			wantReason:     v1beta1.ReasonURLResolved,
+			wantDiscovered: true,
+		},
+		{
+			name:       "TestNewStatusWithEmptyURL",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: v1beta1.ReasonURLNotResolvable,
+		},
+		{
+			name:       "TestNewStatusWithURLError",
+			err:        &urlError{reason: v1beta1.ReasonRouteAPIUnavailable, err: errors.New("openShift Route API not available")},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: v1beta1.ReasonRouteAPIUnavailable,
+		},
+		{
+			name:       "TestNewStatusWithUnknownError",
+			err:        errors.New("boom"),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: v1beta1.ReasonURLDiscoveryFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := newStatus(app, tt.url, tt.err, now)
+			if status.ResolvedURL != tt.wantURL {
+				t.Errorf("newStatus().ResolvedURL = %v, want %v", status.ResolvedURL, tt.wantURL)
+			}
+			if status.ObservedGeneration != 3 {
+				t.Errorf("newStatus().ObservedGeneration = %v, want 3", status.ObservedGeneration)
+			}
+			if (status.LastDiscoveredTime != nil) != tt.wantDiscovered {
+				t.Errorf("newStatus().LastDiscoveredTime = %v, want set = %v", status.LastDiscoveredTime, tt.wantDiscovered)
+			}
+			condition := meta.FindStatusCondition(status.Conditions, v1beta1.ForecastleAppConditionReady)
+			if condition == nil {
+				t.Fatalf("newStatus() has no Ready condition")
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("newStatus() Ready = %v/%v, want %v/%v", condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func Test_statusNeedsUpdate(t *testing.T) {
+	app := *testutil.CreateForecastleApp("app1", "https://app.example.com", "default", "https://icon")
+	discovered := newStatus(app, app.Spec.URL, nil, metav1.Now())
+	app.Status = discovered
+
+	if statusNeedsUpdate(discovered, newStatus(app, app.Spec.URL, nil, metav1.Now())) {
+		t.Errorf("statusNeedsUpdate() = true for an unchanged status discovered again right away")
+	}
+	if !statusNeedsUpdate(discovered, newStatus(app, app.Spec.URL, nil, metav1.NewTime(time.Now().Add(statusRefreshInterval)))) {
+		t.Errorf("statusNeedsUpdate() = false for a status last discovered a refresh interval ago")
+	}
+	if !statusNeedsUpdate(discovered, newStatus(app, "https://other.example.com", nil, metav1.Now())) {
+		t.Errorf("statusNeedsUpdate() = false for a changed URL")
+	}
+	if !statusNeedsUpdate(discovered, newStatus(app, "", errors.New("boom"), metav1.Now())) {
+		t.Errorf("statusNeedsUpdate() = false for a failed discovery")
+	}
+}
+
+func TestList_PopulateWithStatusUpdates(t *testing.T) {
+	clients := kube.Clients{
+		ForecastleAppsClient: fake.NewSimpleClientset(),
+		KubernetesClient:     kubefake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+	}
+
+	apps := []*v1beta1.ForecastleApp{
+		testutil.CreateForecastleApp("app1", "https://app.example.com", "default", "https://icon"),
+		testutil.CreateForecastleAppWithURLFromIngress("app2", "default", "https://icon", "missing-ingress"),
+	}
+	for _, app := range apps {
+		app.Namespace = "default"
+		if _, err := clients.ForecastleAppsClient.ForecastleV1beta1().ForecastleApps("default").Create(app); err != nil {
+			t.Fatalf("Creating forecastleApp %v failed: %v", app.Name, err)
+		}
+	}
+
+	NewList(clients, config.Config{}).WithStatusUpdates(true).Populate("default")
+
+	tests := []struct {
+		name       string
+		wantURL    string
+		wantReason string
+	}{
+		{name: "app1", wantURL: "https://app.example.com", wantReason: v1beta1.ReasonURLResolved},
+		{name: "app2", wantReason: v1beta1.ReasonIngressNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, err := clients.ForecastleAppsClient.ForecastleV1beta1().ForecastleApps("default").Get(tt.name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Getting forecastleApp %v failed: %v", tt.name, err)
+			}
+			if app.Status.ResolvedURL != tt.wantURL {
+				t.Errorf("status.resolvedURL = %v, want %v", app.Status.ResolvedURL, tt.wantURL)
+			}
+			condition := meta.FindStatusCondition(app.Status.Conditions, v1beta1.ForecastleAppConditionReady)
+			if condition == nil || condition.Reason != tt.wantReason {
+				t.Errorf("Ready condition = %v, want reason %v", condition, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestList_PopulateWithoutStatusUpdates(t *testing.T) {
+	clients := kube.Clients{
+		ForecastleAppsClient: fake.NewSimpleClientset(),
+		KubernetesClient:     kubefake.NewSimpleClientset(), //nolint:staticcheck // NewClientset requires generated apply configurations
+	}
+
+	app := testutil.CreateForecastleApp("app1", "https://app.example.com", "default", "https://icon")
+	app.Namespace = "default"
+	if _, err := clients.ForecastleAppsClient.ForecastleV1beta1().ForecastleApps("default").Create(app); err != nil {
+		t.Fatalf("Creating forecastleApp failed: %v", err)
+	}
+
+	NewList(clients, config.Config{}).Populate("default")
+
+	got, err := clients.ForecastleAppsClient.ForecastleV1beta1().ForecastleApps("default").Get("app1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Getting forecastleApp failed: %v", err)
+	}
+	if len(got.Status.Conditions) != 0 {
+		t.Errorf("status was written without status updates enabled: %v", got.Status)
+	}
+}
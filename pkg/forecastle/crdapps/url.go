@@ -3,101 +3,275 @@ package crdapps
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
 
 	routes "github.com/openshift/client-go/route/clientset/versioned"
-	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
 	"github.com/stakater/Forecastle/v1/pkg/kube"
 	"github.com/stakater/Forecastle/v1/pkg/kube/wrappers"
 	ingressroutes "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 )
 
-func getURL(clients kube.Clients, forecastleApp v1alpha1.ForecastleApp) (string, error) {
+// urlError is returned when the URL of a ForecastleApp can't be discovered. It carries the reason
+// reported on the app's Ready condition
+type urlError struct {
+	reason string
+	err    error
+}
+
+func (e *urlError) Error() string {
+	return e.err.Error()
+}
+
+func (e *urlError) Unwrap() error {
+	return e.err
+}
+
+// lookupError wraps an error fetching a referenced object, using notFoundReason if the object doesn't exist
+func lookupError(notFoundReason string, err error) error {
+	if apierrors.IsNotFound(err) {
+		return &urlError{reason: notFoundReason, err: err}
+	}
+	return &urlError{reason: v1beta1.ReasonURLDiscoveryFailed, err: err}
+}
+
+// reasonForError returns the Ready condition reason for an error returned by getURL
+func reasonForError(err error) string {
+	var urlErr *urlError
+	if errors.As(err, &urlErr) {
+		return urlErr.reason
+	}
+	return v1beta1.ReasonURLDiscoveryFailed
+}
+
+// getURL returns the URL of forecastleApp. source is the Ingress or IngressRoute the URL is discovered from, for
+// access detection, nil for other URL sources
+func getURL(clients kube.Clients, appConfig config.Config, forecastleApp v1beta1.ForecastleApp) (url string, source *access.Object, err error) {
 	if len(forecastleApp.Spec.URL) == 0 {
-		return discoverURLFromRefs(clients, forecastleApp)
+		return discoverURLFromRefs(clients, appConfig, forecastleApp)
 
 	}
-	return forecastleApp.Spec.URL, nil
+	return forecastleApp.Spec.URL, nil, nil
 }
 
-func discoverURLFromIngressRef(kubeClient kubernetes.Interface, ingressRef *v1alpha1.IngressURLSource, namespace string) (string, error) {
+func discoverURLFromIngressRef(kubeClient kubernetes.Interface, ingressRef *v1beta1.IngressURLSource, namespace string) (string, *access.Object, error) {
 	ingress, err := kubeClient.NetworkingV1().Ingresses(namespace).Get(context.TODO(), ingressRef.Name, metav1.GetOptions{})
 	if err != nil {
 		logger.Warn("Ingress not found with name " + ingressRef.Name)
-		return "", err
+		return "", nil, lookupError(v1beta1.ReasonIngressNotFound, err)
+	}
+	url, err := wrappers.NewIngressWrapper(ingress).ResolveURL()
+	if err != nil {
+		return "", nil, &urlError{reason: v1beta1.ReasonURLNotResolvable, err: err}
 	}
-	return wrappers.NewIngressWrapper(ingress).GetURL(), nil
+	return url, &access.Object{
+		Kind:        "Ingress",
+		Namespace:   ingress.Namespace,
+		Name:        ingress.Name,
+		Annotations: ingress.Annotations,
+	}, nil
 }
 
-func discoverURLFromRouteRef(routesClient routes.Interface, routeRef *v1alpha1.RouteURLSource, namespace string) (string, error) {
+func discoverURLFromRouteRef(routesClient routes.Interface, routeRef *v1beta1.RouteURLSource, namespace string) (string, error) {
 	route, err := routesClient.RouteV1().Routes(namespace).Get(context.TODO(), routeRef.Name, metav1.GetOptions{})
 	if err != nil {
 		logger.Warn("Route not found with name " + routeRef.Name)
-		return "", err
+		return "", lookupError(v1beta1.ReasonRouteNotFound, err)
 	}
 
 	return wrappers.NewRouteWrapper(route).GetURL(), nil
 }
 
-func discoverURLFromIngressRouteRef(ingressroutesClient ingressroutes.Interface, ingressrouteRef *v1alpha1.IngressRouteURLSource, namespace string) (
-	string, error,
+func discoverURLFromIngressRouteRef(ingressroutesClient ingressroutes.Interface, ingressrouteRef *v1beta1.IngressRouteURLSource, namespace string) (
+	string, *access.Object, error,
 ) {
 	ingressroute, err := ingressroutesClient.TraefikV1alpha1().IngressRoutes(namespace).Get(context.TODO(), ingressrouteRef.Name, metav1.GetOptions{})
 	if err != nil {
 		logger.Warn("IngressRoute not found with name " + ingressrouteRef.Name)
-		return "", err
+		return "", nil, lookupError(v1beta1.ReasonIngressRouteNotFound, err)
 	}
 
-	return wrappers.NewIngressRouteWrapper(ingressroute).GetURL(), nil
+	wrapper := wrappers.NewIngressRouteWrapper(ingressroute)
+	return wrapper.GetURL(), &access.Object{
+		Kind:        "IngressRoute",
+		Namespace:   ingressroute.Namespace,
+		Name:        ingressroute.Name,
+		Annotations: ingressroute.Annotations,
+		Middlewares: wrapper.GetMiddlewares(),
+	}, nil
 }
 
-func discoverURLFromHTTPRouteRef(gatewayClient gateway.Interface, httpRouteRef *v1alpha1.HTTPRouteURLSource, namespace string) (string, error) {
+func discoverURLFromHTTPRouteRef(gatewayClient gateway.Interface, httpRouteRef *v1beta1.HTTPRouteURLSource, namespace string) (string, error) {
 	httpRoute, err := gatewayClient.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(), httpRouteRef.Name, metav1.GetOptions{})
 	if err != nil {
 		logger.Warn("HTTPRoute not found with name " + httpRouteRef.Name)
-		return "", err
+		return "", lookupError(v1beta1.ReasonHTTPRouteNotFound, err)
+	}
+
+	url, err := wrappers.NewHTTPRouteWrapper(httpRoute).ResolveURL()
+	if err != nil {
+		return "", &urlError{reason: v1beta1.ReasonURLNotResolvable, err: err}
+	}
+	return url, nil
+}
+
+// discoverURLFromServiceRef builds the URL of a Service from its load balancer address, or from nodeAddress
+// for NodePort services and load balancers that have no address yet
+func discoverURLFromServiceRef(kubeClient kubernetes.Interface, serviceRef *v1beta1.ServiceURLSource, namespace string, nodeAddress string) (
+	string, error,
+) {
+	service, err := kubeClient.CoreV1().Services(namespace).Get(context.TODO(), serviceRef.Name, metav1.GetOptions{})
+	if err != nil {
+		logger.Warn("Service not found with name " + serviceRef.Name)
+		return "", lookupError(v1beta1.ReasonServiceNotFound, err)
+	}
+
+	port, err := selectServicePort(service, serviceRef.Port)
+	if err != nil {
+		return "", &urlError{reason: v1beta1.ReasonServiceNotExposed, err: err}
 	}
 
-	return wrappers.NewHTTPRouteWrapper(httpRoute).GetURL(), nil
+	var host string
+	var portNumber int32
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		if ingresses := service.Status.LoadBalancer.Ingress; len(ingresses) > 0 {
+			host = ingresses[0].Hostname
+			if host == "" {
+				host = ingresses[0].IP
+			}
+			portNumber = port.Port
+		}
+		if host == "" && port.NodePort != 0 {
+			host, portNumber = nodeAddress, port.NodePort
+		}
+	case corev1.ServiceTypeNodePort:
+		host, portNumber = nodeAddress, port.NodePort
+	default:
+		return "", &urlError{
+			reason: v1beta1.ReasonServiceNotExposed,
+			err:    fmt.Errorf("service %v/%v of type %v is not exposed outside the cluster", namespace, service.Name, service.Spec.Type),
+		}
+	}
+	if host == "" {
+		return "", &urlError{
+			reason: v1beta1.ReasonServiceNotExposed,
+			err:    fmt.Errorf("service %v/%v has no load balancer address and no nodeAddress is configured", namespace, service.Name),
+		}
+	}
+
+	scheme := serviceRef.Scheme
+	if scheme == "" {
+		scheme = "http"
+		if port.Port == 443 || port.Name == "https" {
+			scheme = "https"
+		}
+	}
+
+	hostPort := net.JoinHostPort(host, strconv.Itoa(int(portNumber)))
+	if (scheme == "http" && portNumber == 80) || (scheme == "https" && portNumber == 443) {
+		hostPort = host
+		if strings.Contains(host, ":") {
+			hostPort = "[" + host + "]"
+		}
+	}
+
+	path := serviceRef.Path
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return scheme + "://" + hostPort + path, nil
 }
 
-func discoverURLFromRefs(clients kube.Clients, forecastleApp v1alpha1.ForecastleApp) (string, error) {
+// selectServicePort returns the port of service matching port by name or number, or its first port if port is nil
+func selectServicePort(service *corev1.Service, port *intstr.IntOrString) (corev1.ServicePort, error) {
+	if len(service.Spec.Ports) == 0 {
+		return corev1.ServicePort{}, fmt.Errorf("service %v/%v has no ports", service.Namespace, service.Name)
+	}
+	if port == nil {
+		return service.Spec.Ports[0], nil
+	}
+	for _, servicePort := range service.Spec.Ports {
+		if (port.Type == intstr.String && servicePort.Name == port.StrVal) || (port.Type == intstr.Int && servicePort.Port == port.IntVal) {
+			return servicePort, nil
+		}
+	}
+	return corev1.ServicePort{}, fmt.Errorf("service %v/%v has no port %v", service.Namespace, service.Name, port.String())
+}
+
+func discoverURLFromRefs(clients kube.Clients, appConfig config.Config, forecastleApp v1beta1.ForecastleApp) (string, *access.Object, error) {
 	urlFrom := forecastleApp.Spec.URLFrom
 	if urlFrom == nil {
 		logger.Warn("No URL sources set for ForecastleApp: " + forecastleApp.Name)
-		return "", errors.New("no URL sources set for ForecastleApp: " + forecastleApp.Name)
+		return "", nil, &urlError{reason: v1beta1.ReasonURLSourceMissing, err: errors.New("no URL sources set for ForecastleApp: " + forecastleApp.Name)}
 	}
 
 	if urlFrom.IngressRef != nil {
-		return discoverURLFromIngressRef(clients.KubernetesClient, urlFrom.IngressRef, forecastleApp.Namespace)
+		namespace, err := resolveRefNamespace(clients.GatewayClient, forecastleApp, ingressGroupKind, urlFrom.IngressRef.ObjectReference)
+		if err != nil {
+			return "", nil, err
+		}
+		return discoverURLFromIngressRef(clients.KubernetesClient, urlFrom.IngressRef, namespace)
 	}
 
 	if urlFrom.RouteRef != nil {
 		if clients.RoutesClient == nil {
 			logger.Warnf("RouteRef specified on '%s' but OpenShift Route API not available", forecastleApp.Name)
-			return "", errors.New("openShift Route API not available")
+			return "", nil, &urlError{reason: v1beta1.ReasonRouteAPIUnavailable, err: errors.New("openShift Route API not available")}
 		}
-		return discoverURLFromRouteRef(clients.RoutesClient, urlFrom.RouteRef, forecastleApp.Namespace)
+		namespace, err := resolveRefNamespace(clients.GatewayClient, forecastleApp, routeGroupKind, urlFrom.RouteRef.ObjectReference)
+		if err != nil {
+			return "", nil, err
+		}
+		url, err := discoverURLFromRouteRef(clients.RoutesClient, urlFrom.RouteRef, namespace)
+		return url, nil, err
 	}
 
 	if urlFrom.IngressRouteRef != nil {
 		if clients.IngressRoutesClient == nil {
 			logger.Warnf("IngressRouteRef specified on '%s' but Traefik API not available", forecastleApp.Name)
-			return "", errors.New("traefik IngressRoute API not available")
+			return "", nil, &urlError{reason: v1beta1.ReasonIngressRouteAPIUnavailable, err: errors.New("traefik IngressRoute API not available")}
+		}
+		namespace, err := resolveRefNamespace(clients.GatewayClient, forecastleApp, ingressRouteGroupKind, urlFrom.IngressRouteRef.ObjectReference)
+		if err != nil {
+			return "", nil, err
 		}
-		return discoverURLFromIngressRouteRef(clients.IngressRoutesClient, urlFrom.IngressRouteRef, forecastleApp.Namespace)
+		return discoverURLFromIngressRouteRef(clients.IngressRoutesClient, urlFrom.IngressRouteRef, namespace)
 	}
 
 	if urlFrom.HTTPRouteRef != nil {
 		if clients.GatewayClient == nil {
 			logger.Warnf("HTTPRouteRef specified on '%s' but Gateway API not available", forecastleApp.Name)
-			return "", errors.New("gateway API not available")
+			return "", nil, &urlError{reason: v1beta1.ReasonGatewayAPIUnavailable, err: errors.New("gateway API not available")}
+		}
+		namespace, err := resolveRefNamespace(clients.GatewayClient, forecastleApp, httpRouteGroupKind, urlFrom.HTTPRouteRef.ObjectReference)
+		if err != nil {
+			return "", nil, err
+		}
+		url, err := discoverURLFromHTTPRouteRef(clients.GatewayClient, urlFrom.HTTPRouteRef, namespace)
+		return url, nil, err
+	}
+
+	if urlFrom.ServiceRef != nil {
+		namespace, err := resolveRefNamespace(clients.GatewayClient, forecastleApp, serviceGroupKind, urlFrom.ServiceRef.ObjectReference)
+		if err != nil {
+			return "", nil, err
 		}
-		return discoverURLFromHTTPRouteRef(clients.GatewayClient, urlFrom.HTTPRouteRef, forecastleApp.Namespace)
+		url, err := discoverURLFromServiceRef(clients.KubernetesClient, urlFrom.ServiceRef, namespace, appConfig.NodeAddress)
+		return url, nil, err
 	}
 
 	logger.Warn("Unsupported Ref set on ForecastleApp: " + forecastleApp.Name)
-	return "", errors.New("unsupported Ref set on ForecastleApp: " + forecastleApp.Name)
+	return "", nil, &urlError{reason: v1beta1.ReasonURLSourceUnsupported, err: errors.New("unsupported Ref set on ForecastleApp: " + forecastleApp.Name)}
 }
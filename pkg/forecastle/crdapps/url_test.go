@@ -5,10 +5,13 @@ import (
 	"testing"
 
 	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
-	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/kube"
 	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 )
 
@@ -19,7 +22,7 @@ func Test_getURL(t *testing.T) {
 	}
 	type args struct {
 		clients       kube.Clients
-		forecastleApp v1alpha1.ForecastleApp
+		forecastleApp v1beta1.ForecastleApp
 	}
 	tests := []struct {
 		name string
@@ -46,7 +49,7 @@ func Test_getURL(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, err := getURL(tt.args.clients, tt.args.forecastleApp); got != tt.want && err != tt.err {
+			if got, _, err := getURL(tt.args.clients, config.Config{}, tt.args.forecastleApp); got != tt.want && err != tt.err {
 				t.Errorf("getURL() = %v, want %v, err = %v, wantErr = %v", got, tt.want, err, tt.err)
 			}
 		})
@@ -68,7 +71,7 @@ func Test_discoverURLFromRefs(t *testing.T) {
 	}
 	type args struct {
 		clients       kube.Clients
-		forecastleApp v1alpha1.ForecastleApp
+		forecastleApp v1beta1.ForecastleApp
 	}
 	tests := []struct {
 		name string
@@ -95,7 +98,7 @@ func Test_discoverURLFromRefs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, err := discoverURLFromRefs(tt.args.clients, tt.args.forecastleApp); got != tt.want && err != tt.err {
+			if got, _, err := discoverURLFromRefs(tt.args.clients, config.Config{}, tt.args.forecastleApp); got != tt.want && err != tt.err {
 				t.Errorf("discoverURLFromRefs() = %v, want %v, err = %v, wantErr = %v", got, tt.want, err, tt.err)
 			}
 		})
@@ -110,3 +113,125 @@ func Test_discoverURLFromRefs(t *testing.T) {
 		t.Errorf("Deleting my-app-route Failed")
 	}
 }
+
+func Test_discoverURLFromServiceRef(t *testing.T) {
+	httpsPort := intstr.FromString("https")
+	adminPort := intstr.FromInt32(9000)
+	missingPort := intstr.FromString("missing")
+
+	tests := []struct {
+		name        string
+		serviceType corev1.ServiceType
+		ingress     []corev1.LoadBalancerIngress
+		ref         v1beta1.ServiceURLSource
+		nodeAddress string
+		want        string
+		wantReason  string
+	}{
+		{
+			name:        "TestLoadBalancerHostname",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			ingress:     []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			want:        "http://lb.example.com",
+		},
+		{
+			name:        "TestLoadBalancerIPWithNamedHTTPSPort",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			ingress:     []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+			ref:         v1beta1.ServiceURLSource{Port: &httpsPort, Path: "dashboard"},
+			want:        "https://10.0.0.1/dashboard",
+		},
+		{
+			name:        "TestLoadBalancerIPv6WithPort",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			ingress:     []corev1.LoadBalancerIngress{{IP: "2001:db8::1"}},
+			ref:         v1beta1.ServiceURLSource{Port: &adminPort},
+			want:        "http://[2001:db8::1]:9000",
+		},
+		{
+			name:        "TestLoadBalancerIPv6DefaultPort",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			ingress:     []corev1.LoadBalancerIngress{{IP: "2001:db8::1"}},
+			want:        "http://[2001:db8::1]",
+		},
+		{
+			name:        "TestPendingLoadBalancerUsesNodePort",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			nodeAddress: "node.example.com",
+			want:        "http://node.example.com:30080",
+		},
+		{
+			name:        "TestNodePortWithScheme",
+			serviceType: corev1.ServiceTypeNodePort,
+			ref:         v1beta1.ServiceURLSource{Scheme: "https", Path: "/ui"},
+			nodeAddress: "node.example.com",
+			want:        "https://node.example.com:30080/ui",
+		},
+		{
+			name:        "TestNodePortWithoutNodeAddress",
+			serviceType: corev1.ServiceTypeNodePort,
+			wantReason:  v1beta1.ReasonServiceNotExposed,
+		},
+		{
+			name:        "TestClusterIP",
+			serviceType: corev1.ServiceTypeClusterIP,
+			nodeAddress: "node.example.com",
+			wantReason:  v1beta1.ReasonServiceNotExposed,
+		},
+		{
+			name:        "TestMissingPort",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			ingress:     []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			ref:         v1beta1.ServiceURLSource{Port: &missingPort},
+			wantReason:  v1beta1.ReasonServiceNotExposed,
+		},
+		{
+			name:       "TestServiceNotFound",
+			ref:        v1beta1.ServiceURLSource{ObjectReference: v1beta1.ObjectReference{Name: "missing"}},
+			wantReason: v1beta1.ReasonServiceNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: corev1.ServiceSpec{
+					Type: tt.serviceType,
+					Ports: []corev1.ServicePort{
+						{Name: "http", Port: 80, NodePort: 30080},
+						{Name: "https", Port: 443, NodePort: 30443},
+						{Name: "admin", Port: 9000, NodePort: 30900},
+					},
+				},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: tt.ingress}},
+			}
+			kubeClient := kubefake.NewSimpleClientset(service) //nolint:staticcheck // NewClientset requires generated apply configurations
+
+			ref := tt.ref
+			if ref.Name == "" {
+				ref.Name = "app"
+			}
+			forecastleApp := v1beta1.ForecastleApp{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: v1beta1.ForecastleAppSpec{
+					Name:    "app",
+					URLFrom: &v1beta1.URLSource{ServiceRef: &ref},
+				},
+			}
+
+			got, _, err := discoverURLFromRefs(kube.Clients{KubernetesClient: kubeClient}, config.Config{NodeAddress: tt.nodeAddress}, forecastleApp)
+			if tt.wantReason != "" {
+				if err == nil || reasonForError(err) != tt.wantReason {
+					t.Fatalf("discoverURLFromRefs() error = %v, want reason %v", err, tt.wantReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("discoverURLFromRefs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("discoverURLFromRefs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
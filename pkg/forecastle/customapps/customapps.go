@@ -5,12 +5,13 @@ import (
 
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/kube/wrappers"
+	"github.com/stakater/Forecastle/v1/pkg/log"
 )
 
-// un-used variable
-// var (
-// 	logger = log.New()
-// )
+var (
+	logger = log.New()
+)
 
 // List struct is used for listing forecastle apps
 type List struct {
@@ -39,17 +40,46 @@ func (al *List) Get() ([]forecastle.App, error) {
 }
 
 func convertCustomAppsToForecastleApps(customApps []config.CustomApp) (apps []forecastle.App) {
-	for _, customApp := range customApps {
+	for index, customApp := range customApps {
 		apps = append(apps, forecastle.App{
 			Name:              customApp.Name,
 			URL:               customApp.URL,
 			Icon:              customApp.Icon,
 			Group:             strings.ToLower(customApp.Group),
+			GroupDisplayName:  customApp.Group,
+			Description:       customApp.Description,
+			Tags:              customApp.Tags,
+			Weight:            customApp.Weight,
+			Links:             convertLinks(customApp),
+			AppID:             customApp.AppID,
 			DiscoverySource:   forecastle.Config,
 			NetworkRestricted: customApp.NetworkRestricted,
+			RequiresAuth:      customApp.RequiresAuth,
+			AllowedGroups:     customApp.AllowedGroups,
 			Properties:        customApp.Properties,
+			Origin: &forecastle.Origin{
+				Kind:  "CustomApp",
+				Name:  customApp.Name,
+				Index: &index,
+			},
 		})
 	}
 
 	return apps
 }
+
+// convertLinks converts the links of customApp, skipping links without a valid URL
+func convertLinks(customApp config.CustomApp) []forecastle.Link {
+	if len(customApp.Links) == 0 {
+		return nil
+	}
+	links := make([]forecastle.Link, 0, len(customApp.Links))
+	for _, link := range customApp.Links {
+		links = append(links, forecastle.Link{Label: link.Label, URL: link.URL, Icon: link.Icon})
+	}
+	valid, errs := wrappers.ValidLinks(links)
+	for _, err := range errs {
+		logger.Warnf("Skipping link of custom app '%v': %v", customApp.Name, err)
+	}
+	return valid
+}
@@ -37,6 +37,7 @@ func TestNewList(t *testing.T) {
 }
 
 func TestList_Populate(t *testing.T) {
+	firstIndex := 0
 	type fields struct {
 		appConfig config.Config
 		err       error
@@ -74,11 +75,13 @@ func TestList_Populate(t *testing.T) {
 				},
 				items: []forecastle.App{
 					{
-						Name:            "Test",
-						URL:             "http://google.com",
-						Icon:            "http://google.com",
-						Group:           "my group", // Normalized to lowercase for case-insensitive grouping
-						DiscoverySource: forecastle.Config,
+						Name:             "Test",
+						URL:              "http://google.com",
+						Icon:             "http://google.com",
+						Group:            "my group", // Normalized to lowercase for case-insensitive grouping
+						GroupDisplayName: "My Group",
+						DiscoverySource:  forecastle.Config,
+						Origin:           &forecastle.Origin{Kind: "CustomApp", Name: "Test", Index: &firstIndex},
 					},
 				},
 			},
@@ -98,6 +101,21 @@ func TestList_Populate(t *testing.T) {
 	}
 }
 
+func Test_convertLinks(t *testing.T) {
+	customApp := config.CustomApp{
+		Name: "Test",
+		Links: []config.Link{
+			{Label: "Docs", URL: "https://docs.example.com", Icon: "https://docs.example.com/icon.png"},
+			{Label: "Runbook", URL: "wiki.example.com/runbook"},
+			{URL: "https://grafana.example.com"},
+		},
+	}
+	want := []forecastle.Link{{Label: "Docs", URL: "https://docs.example.com", Icon: "https://docs.example.com/icon.png"}}
+	if got := convertLinks(customApp); !reflect.DeepEqual(got, want) {
+		t.Errorf("convertLinks() = %v, want %v", got, want)
+	}
+}
+
 func TestList_Get(t *testing.T) {
 	type fields struct {
 		appConfig config.Config
@@ -155,6 +173,7 @@ func TestList_Get(t *testing.T) {
 }
 
 func Test_convertCustomAppsToForecastleApps(t *testing.T) {
+	firstIndex := 0
 	type args struct {
 		customApps []config.CustomApp
 	}
@@ -184,11 +203,13 @@ func Test_convertCustomAppsToForecastleApps(t *testing.T) {
 			},
 			wantApps: []forecastle.App{
 				forecastle.App{
-					Name:            "test",
-					Icon:            "http://google.com/image.png",
-					Group:           "new", // Normalized to lowercase for case-insensitive grouping
-					URL:             "http://google.com",
-					DiscoverySource: forecastle.Config,
+					Name:             "test",
+					Icon:             "http://google.com/image.png",
+					Group:            "new", // Normalized to lowercase for case-insensitive grouping
+					GroupDisplayName: "New",
+					URL:              "http://google.com",
+					DiscoverySource:  forecastle.Config,
+					Origin:           &forecastle.Origin{Kind: "CustomApp", Name: "test", Index: &firstIndex},
 				},
 			},
 		},
@@ -0,0 +1,122 @@
+package derivation
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+)
+
+var (
+	logger = log.New()
+)
+
+// Metadata is the data the derivation templates are evaluated against
+type Metadata struct {
+	// Kind is the kind of the object the app is discovered from, e.g. Ingress, HTTPRoute or ForecastleApp
+	Kind                 string
+	Name                 string
+	Namespace            string
+	Labels               map[string]string
+	Annotations          map[string]string
+	NamespaceLabels      map[string]string
+	NamespaceAnnotations map[string]string
+	// Host is the host of the app, empty if it isn't known
+	Host string
+}
+
+// Rules derives the name and group of apps that don't set them explicitly. A nil Rules derives nothing
+type Rules struct {
+	name  []*template.Template
+	group []*template.Template
+}
+
+// NewRules parses the derivation templates of the config. Invalid templates are logged and skipped.
+// Returns nil if no templates are configured
+func NewRules(derivation config.Derivation) *Rules {
+	if len(derivation.Name) == 0 && len(derivation.Group) == 0 {
+		return nil
+	}
+	return &Rules{
+		name:  parseTemplates("name", derivation.Name),
+		group: parseTemplates("group", derivation.Group),
+	}
+}
+
+// Name returns the output of the first name template that renders a non empty value
+func (r *Rules) Name(metadata Metadata) string {
+	if r == nil {
+		return ""
+	}
+	return execute(r.name, metadata)
+}
+
+// Group returns the output of the first group template that renders a non empty value
+func (r *Rules) Group(metadata Metadata) string {
+	if r == nil {
+		return ""
+	}
+	return execute(r.group, metadata)
+}
+
+func parseTemplates(field string, texts []string) []*template.Template {
+	var templates []*template.Template
+	for i, text := range texts {
+		tmpl, err := template.New(field).Funcs(funcs).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			logger.Warnf("Ignoring invalid %s derivation template %d: %v", field, i, err)
+			continue
+		}
+		templates = append(templates, tmpl)
+	}
+	return templates
+}
+
+func execute(templates []*template.Template, metadata Metadata) string {
+	for _, tmpl := range templates {
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, metadata); err != nil {
+			logger.Warnf("Error evaluating %s derivation template for %s '%s' in Namespace '%s': %v",
+				tmpl.Name(), metadata.Kind, metadata.Name, metadata.Namespace, err)
+			continue
+		}
+		if value := strings.TrimSpace(out.String()); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// funcs are the functions available to derivation templates. Arguments follow the order of the sprig
+// functions of the same name, so values can be piped into them
+var funcs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"default": func(defaultValue, s string) string {
+		if s == "" {
+			return defaultValue
+		}
+		return s
+	},
+	"regexFind": func(expr, s string) (string, error) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return "", err
+		}
+		return re.FindString(s), nil
+	},
+	"regexReplaceAll": func(expr, s, replacement string) (string, error) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return "", err
+		}
+		return re.ReplaceAllString(s, replacement), nil
+	},
+}
@@ -0,0 +1,67 @@
+package derivation
+
+import (
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+)
+
+func TestRules(t *testing.T) {
+	metadata := Metadata{
+		Kind:            "Ingress",
+		Name:            "grafana-ingress",
+		Namespace:       "team-a-monitoring",
+		Labels:          map[string]string{"app.kubernetes.io/name": "Grafana"},
+		NamespaceLabels: map[string]string{"team": "A"},
+		Host:            "grafana.apps.example.com",
+	}
+
+	tests := []struct {
+		name       string
+		derivation config.Derivation
+		wantName   string
+		wantGroup  string
+	}{
+		{
+			name: "NoTemplates",
+		},
+		{
+			name: "Labels",
+			derivation: config.Derivation{
+				Name:  []string{`{{ index .Labels "app.kubernetes.io/name" }}`},
+				Group: []string{`Team {{ index .NamespaceLabels "team" }}`},
+			},
+			wantName:  "Grafana",
+			wantGroup: "Team A",
+		},
+		{
+			name: "FirstNonEmptyTemplateWins",
+			derivation: config.Derivation{
+				Name:  []string{`{{ index .Labels "app.kubernetes.io/instance" }}`, `{{ .Host | regexFind "^[^.]+" }}`},
+				Group: []string{`{{ .Namespace | trimPrefix "team-a-" | upper }}`},
+			},
+			wantName:  "grafana",
+			wantGroup: "MONITORING",
+		},
+		{
+			name: "InvalidTemplatesAreSkipped",
+			derivation: config.Derivation{
+				Name:  []string{`{{ .Name`, `{{ regexFind "(" .Host }}`, `{{ .Name | replace "-ingress" "" }}`},
+				Group: []string{`{{ .Unknown }}`, `{{ index .Labels "missing" | default .Namespace }}`},
+			},
+			wantName:  "grafana",
+			wantGroup: "team-a-monitoring",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := NewRules(tt.derivation)
+			if got := rules.Name(metadata); got != tt.wantName {
+				t.Errorf("Rules.Name() = %q, want %q", got, tt.wantName)
+			}
+			if got := rules.Group(metadata); got != tt.wantGroup {
+				t.Errorf("Rules.Group() = %q, want %q", got, tt.wantGroup)
+			}
+		})
+	}
+}
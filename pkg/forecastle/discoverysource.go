@@ -39,18 +39,27 @@ func (ds *DiscoverySource) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	parsed, err := ParseDiscoverySource(s)
+	if err != nil {
+		return err
+	}
+
+	*ds = parsed
+	return nil
+}
+
+// ParseDiscoverySource returns the DiscoverySource matching its string representation
+func ParseDiscoverySource(s string) (DiscoverySource, error) {
 	switch s {
 	case "Ingress":
-		*ds = Ingress
+		return Ingress, nil
 	case "Config":
-		*ds = Config
+		return Config, nil
 	case "ForecastleAppCRD":
-		*ds = ForecastleAppCRD
+		return ForecastleAppCRD, nil
 	case "HTTPRoute":
-		*ds = HTTPRoute
+		return HTTPRoute, nil
 	default:
-		return fmt.Errorf("unknown DiscoverySource: %s", s)
+		return 0, fmt.Errorf("unknown DiscoverySource: %s", s)
 	}
-
-	return nil
 }
@@ -1,6 +1,8 @@
 package filters
 
 import (
+	"slices"
+
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/util/strings"
@@ -30,3 +32,18 @@ func ByForecastleInstanceAnnotation(annots map[string]string, appConfig config.C
 func ByInstance(instanceValue string, appConfig config.Config) bool {
 	return strings.ContainsBetweenDelimiter(instanceValue, appConfig.InstanceName, ",")
 }
+
+// ByIngressClass returns true if the ingress class name is one of the configured ingress classes
+func ByIngressClass(ingressClassName string, appConfig config.Config) bool {
+	return ingressClassName != "" && slices.Contains(appConfig.IngressClasses, ingressClassName)
+}
+
+// ByGatewayClass returns true if any of the gateway class names is one of the configured gateway classes
+func ByGatewayClass(gatewayClassNames []string, appConfig config.Config) bool {
+	for _, gatewayClassName := range gatewayClassNames {
+		if slices.Contains(appConfig.GatewayClasses, gatewayClassName) {
+			return true
+		}
+	}
+	return false
+}
@@ -135,3 +135,85 @@ func TestByInstance(t *testing.T) {
 		})
 	}
 }
+
+func TestByIngressClass(t *testing.T) {
+	tests := []struct {
+		name             string
+		ingressClassName string
+		appConfig        config.Config
+		want             bool
+	}{
+		{
+			name:             "EmptyIngressClassName",
+			ingressClassName: "",
+			appConfig:        config.Config{IngressClasses: []string{"internal"}},
+			want:             false,
+		},
+		{
+			name:             "IngressClassMatches",
+			ingressClassName: "internal",
+			appConfig:        config.Config{IngressClasses: []string{"internal"}},
+			want:             true,
+		},
+		{
+			name:             "IngressClassInList",
+			ingressClassName: "public",
+			appConfig:        config.Config{IngressClasses: []string{"internal", "public"}},
+			want:             true,
+		},
+		{
+			name:             "IngressClassNotInList",
+			ingressClassName: "public",
+			appConfig:        config.Config{IngressClasses: []string{"internal"}},
+			want:             false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ByIngressClass(tt.ingressClassName, tt.appConfig); got != tt.want {
+				t.Errorf("ByIngressClass() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByGatewayClass(t *testing.T) {
+	tests := []struct {
+		name              string
+		gatewayClassNames []string
+		appConfig         config.Config
+		want              bool
+	}{
+		{
+			name:              "NoGatewayClassNames",
+			gatewayClassNames: nil,
+			appConfig:         config.Config{GatewayClasses: []string{"internal"}},
+			want:              false,
+		},
+		{
+			name:              "GatewayClassMatches",
+			gatewayClassNames: []string{"internal"},
+			appConfig:         config.Config{GatewayClasses: []string{"internal"}},
+			want:              true,
+		},
+		{
+			name:              "OneOfMultipleParentsMatches",
+			gatewayClassNames: []string{"public", "internal"},
+			appConfig:         config.Config{GatewayClasses: []string{"internal"}},
+			want:              true,
+		},
+		{
+			name:              "GatewayClassNotInList",
+			gatewayClassNames: []string{"public"},
+			appConfig:         config.Config{GatewayClasses: []string{"internal"}},
+			want:              false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ByGatewayClass(tt.gatewayClassNames, tt.appConfig); got != tt.want {
+				t.Errorf("ByGatewayClass() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,133 @@
 package forecastle
 
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // App struct that contains information about an app that is exposed to forecastle
 type App struct {
-	Name              string            `json:"name"`
-	Icon              string            `json:"icon"`
-	Group             string            `json:"group"`
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Icon             string `json:"icon"`
+	Group            string `json:"group"`
+	GroupDisplayName string `json:"groupDisplayName,omitempty"`
+	// ParentGroup is the slug of the group containing the app's group, empty for top level groups
+	ParentGroup       string            `json:"parentGroup,omitempty"`
 	URL               string            `json:"url"`
+	Description       string            `json:"description,omitempty"`
+	Tags              []string          `json:"tags,omitempty"`
+	Weight            int               `json:"weight,omitempty"`
+	Links             []Link            `json:"links,omitempty"`
+	Version           string            `json:"version,omitempty"`
+	Component         string            `json:"component,omitempty"`
+	ManagedBy         string            `json:"managedBy,omitempty"`
+	AppID             string            `json:"appId,omitempty"`
 	DiscoverySource   DiscoverySource   `json:"discoverySource"`
+	DiscoverySources  []DiscoverySource `json:"discoverySources,omitempty"`
 	NetworkRestricted bool              `json:"networkRestricted"`
-	Properties        map[string]string `json:"properties,omitempty"`
+	RequiresAuth      bool              `json:"requiresAuth"`
+	// Unreachable is set on network restricted apps returned to clients outside the internal networks
+	Unreachable bool              `json:"unreachable,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+	Origin      *Origin           `json:"origin,omitempty"`
+	// PropertyTypes holds the types of properties keyed like Properties, one of text, url, number or boolean.
+	// Properties without a type are text
+	PropertyTypes map[string]string `json:"propertyTypes,omitempty"`
+	// AllowedGroups are the user groups allowed to see the app, everyone if empty. It isn't returned to
+	// users, so the group names of an organization aren't disclosed
+	AllowedGroups []string `json:"-"`
+	// Annotations holds the raw forecastle annotations the app was built from
+	Annotations map[string]string `json:"-"`
+	// GroupFromNamespace is set when the group was derived from the name of the app's namespace
+	GroupFromNamespace bool `json:"-"`
+}
+
+// Link struct is a secondary link of an app, such as its docs, runbook or dashboard
+type Link struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+	Icon  string `json:"icon,omitempty"`
+}
+
+// HasTag returns true if the app carries tag, compared case insensitively
+func (app App) HasTag(tag string) bool {
+	for _, appTag := range app.Tags {
+		if strings.EqualFold(appTag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVisibleTo returns true if a user in userGroups may see the app
+func (app App) IsVisibleTo(userGroups []string) bool {
+	if len(app.AllowedGroups) == 0 {
+		return true
+	}
+	for _, group := range userGroups {
+		if slices.Contains(app.AllowedGroups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// SortApps orders apps by the order of their group, then group, weight and name. groupOrder returns the
+// order of a group slug
+func SortApps(apps []App, groupOrder func(group string) int) {
+	slices.SortStableFunc(apps, func(a, b App) int {
+		return cmp.Or(
+			cmp.Compare(groupOrder(a.Group), groupOrder(b.Group)),
+			cmp.Compare(a.Group, b.Group),
+			cmp.Compare(a.Weight, b.Weight),
+			cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)),
+		)
+	})
+}
+
+// Origin struct describes the object an app was discovered from
+type Origin struct {
+	Kind              string       `json:"kind"`
+	Namespace         string       `json:"namespace,omitempty"`
+	Name              string       `json:"name"`
+	CreationTimestamp *metav1.Time `json:"creationTimestamp,omitempty"`
+	// Index is the position of the app in the customApps config, only set for apps from config
+	Index *int `json:"index,omitempty"`
+}
+
+// NewOrigin returns the origin of an app discovered from the kubernetes object with the given kind and metadata
+func NewOrigin(kind string, objectMeta metav1.ObjectMeta) *Origin {
+	origin := &Origin{
+		Kind:      kind,
+		Namespace: objectMeta.Namespace,
+		Name:      objectMeta.Name,
+	}
+	if !objectMeta.CreationTimestamp.IsZero() {
+		creationTimestamp := objectMeta.CreationTimestamp
+		origin.CreationTimestamp = &creationTimestamp
+	}
+	return origin
+}
+
+// NewID returns a stable identifier for an app derived from the cluster, its discovery source and
+// the object it was discovered from. Apps from config are identified by their index in the config
+func NewID(clusterName string, source DiscoverySource, origin *Origin) string {
+	parts := []string{clusterName, source.String()}
+	if origin != nil {
+		if origin.Index != nil {
+			parts = append(parts, strconv.Itoa(*origin.Index))
+		} else {
+			parts = append(parts, origin.Namespace, origin.Name)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "/")))
+	return hex.EncodeToString(sum[:8])
 }
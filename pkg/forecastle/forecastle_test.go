@@ -0,0 +1,31 @@
+package forecastle
+
+import (
+	"testing"
+)
+
+func TestNewID(t *testing.T) {
+	first, second := 0, 1
+	ingressOrigin := &Origin{Kind: "Ingress", Namespace: "default", Name: "app"}
+
+	if NewID("prod", Ingress, ingressOrigin) != NewID("prod", Ingress, &Origin{Kind: "Ingress", Namespace: "default", Name: "app"}) {
+		t.Errorf("NewID() is not stable for the same origin")
+	}
+
+	distinct := map[string]string{
+		"ingress":        NewID("prod", Ingress, ingressOrigin),
+		"otherCluster":   NewID("staging", Ingress, ingressOrigin),
+		"otherSource":    NewID("prod", HTTPRoute, &Origin{Kind: "HTTPRoute", Namespace: "default", Name: "app"}),
+		"otherNamespace": NewID("prod", Ingress, &Origin{Kind: "Ingress", Namespace: "tools", Name: "app"}),
+		"firstConfig":    NewID("prod", Config, &Origin{Kind: "CustomApp", Name: "app", Index: &first}),
+		"secondConfig":   NewID("prod", Config, &Origin{Kind: "CustomApp", Name: "app", Index: &second}),
+	}
+
+	seen := map[string]string{}
+	for name, id := range distinct {
+		if other, ok := seen[id]; ok {
+			t.Errorf("NewID() returned the same id for %s and %s", name, other)
+		}
+		seen[id] = name
+	}
+}
@@ -0,0 +1,177 @@
+package groups
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+)
+
+var (
+	logger = log.New()
+)
+
+// Node is a group in the group hierarchy with the apps directly in it and its nested groups
+type Node struct {
+	// Slug is the full lowercase path of the group, e.g. platform/observability
+	Slug        string           `json:"slug"`
+	Name        string           `json:"name"`
+	Icon        string           `json:"icon,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Order       int              `json:"order,omitempty"`
+	Collapsed   bool             `json:"collapsed,omitempty"`
+	Apps        []forecastle.App `json:"apps"`
+	Children    []*Node          `json:"children,omitempty"`
+}
+
+// Apply nests the groups of apps according to the group hierarchy config. Groups derived from a namespace
+// are placed under the parent of the first matching namespace rule, group paths are normalized, and the
+// parent group and configured display name of every app are set
+func Apply(apps []forecastle.App, appConfig config.Config) {
+	separator := appConfig.GetGroupSeparator()
+	rules := compileRules(appConfig.GroupHierarchy.NamespaceRules)
+
+	for i := range apps {
+		app := &apps[i]
+		displayName := app.GroupDisplayName
+		if displayName == "" {
+			displayName = app.Group
+		}
+
+		if app.GroupFromNamespace && app.Origin != nil {
+			if parent := namespaceParent(rules, app.Origin.Namespace); parent != "" {
+				displayName = parent + separator + displayName
+			}
+		}
+
+		segments := split(displayName, separator)
+		displayName = strings.Join(segments, separator)
+		app.Group = strings.ToLower(displayName)
+		app.GroupDisplayName = displayName
+		if len(segments) > 1 {
+			app.ParentGroup = strings.ToLower(strings.Join(segments[:len(segments)-1], separator))
+		}
+
+		if group, ok := appConfig.GetGroup(app.Group); ok && group.DisplayName != "" {
+			app.GroupDisplayName = group.DisplayName
+		}
+	}
+}
+
+// Tree returns the group hierarchy of apps, which must have been passed through Apply. Every level is
+// sorted by group order and then slug, apps keep their order
+func Tree(apps []forecastle.App, appConfig config.Config) []*Node {
+	separator := appConfig.GetGroupSeparator()
+
+	root := &Node{}
+	nodes := map[string]*Node{}
+
+	for _, app := range apps {
+		slugs := split(app.Group, separator)
+		names := split(app.GroupDisplayName, separator)
+		if len(names) != len(slugs) {
+			// The display name was replaced by the group config, only its last segment is known
+			names = nil
+		}
+
+		parent := root
+		for depth := range slugs {
+			slug := strings.Join(slugs[:depth+1], separator)
+			node, ok := nodes[slug]
+			if !ok {
+				name := slugs[depth]
+				if names != nil {
+					name = names[depth]
+				}
+				node = newNode(slug, name, appConfig)
+				nodes[slug] = node
+				parent.Children = append(parent.Children, node)
+			}
+			parent = node
+		}
+		if len(slugs) == 0 {
+			parent = nodes[""]
+			if parent == nil {
+				parent = newNode("", "", appConfig)
+				nodes[""] = parent
+				root.Children = append(root.Children, parent)
+			}
+		}
+		parent.Apps = append(parent.Apps, app)
+	}
+
+	sortNodes(root.Children)
+	return root.Children
+}
+
+func newNode(slug string, name string, appConfig config.Config) *Node {
+	node := &Node{
+		Slug: slug,
+		Name: name,
+		Apps: []forecastle.App{},
+	}
+	if group, ok := appConfig.GetGroup(slug); ok {
+		if group.DisplayName != "" {
+			node.Name = group.DisplayName
+		}
+		node.Icon = group.Icon
+		node.Description = group.Description
+		node.Order = group.Order
+		node.Collapsed = group.Collapsed
+	}
+	return node
+}
+
+func sortNodes(nodes []*Node) {
+	slices.SortStableFunc(nodes, func(a, b *Node) int {
+		return cmp.Or(cmp.Compare(a.Order, b.Order), cmp.Compare(a.Slug, b.Slug))
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+// split returns the non empty, trimmed segments of a group path
+func split(group string, separator string) []string {
+	var segments []string
+	for _, segment := range strings.Split(group, separator) {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+type namespaceRule struct {
+	namespace *regexp.Regexp
+	parent    string
+}
+
+func compileRules(rules []config.NamespaceGroupRule) []namespaceRule {
+	var compiled []namespaceRule
+	for _, rule := range rules {
+		namespace, err := regexp.Compile(rule.Namespace)
+		if err != nil {
+			logger.Warnf("Ignoring group namespace rule with invalid namespace expression %q: %v", rule.Namespace, err)
+			continue
+		}
+		compiled = append(compiled, namespaceRule{namespace: namespace, parent: rule.Parent})
+	}
+	return compiled
+}
+
+// namespaceParent returns the parent group of the first rule matching namespace, expanding capture groups
+func namespaceParent(rules []namespaceRule, namespace string) string {
+	for _, rule := range rules {
+		match := rule.namespace.FindStringSubmatchIndex(namespace)
+		if match == nil {
+			continue
+		}
+		return string(rule.namespace.ExpandString(nil, rule.parent, namespace, match))
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package groups
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		apps      []forecastle.App
+		appConfig config.Config
+		want      []forecastle.App
+	}{
+		{
+			name: "FlatGroup",
+			apps: []forecastle.App{
+				{Name: "a", Group: "dev", GroupDisplayName: "Dev"},
+			},
+			want: []forecastle.App{
+				{Name: "a", Group: "dev", GroupDisplayName: "Dev"},
+			},
+		},
+		{
+			name: "NestedGroupIsNormalized",
+			apps: []forecastle.App{
+				{Name: "a", Group: "platform / observability/", GroupDisplayName: "Platform / Observability/"},
+			},
+			want: []forecastle.App{
+				{Name: "a", Group: "platform/observability", GroupDisplayName: "Platform/Observability", ParentGroup: "platform"},
+			},
+		},
+		{
+			name: "CustomSeparator",
+			apps: []forecastle.App{
+				{Name: "a", Group: "platform/ci", GroupDisplayName: "Platform/CI"},
+				{Name: "b", Group: "platform.ci", GroupDisplayName: "Platform.CI"},
+			},
+			appConfig: config.Config{GroupHierarchy: config.GroupHierarchy{Separator: "."}},
+			want: []forecastle.App{
+				{Name: "a", Group: "platform/ci", GroupDisplayName: "Platform/CI"},
+				{Name: "b", Group: "platform.ci", GroupDisplayName: "Platform.CI", ParentGroup: "platform"},
+			},
+		},
+		{
+			name: "NamespaceRuleWithCapture",
+			apps: []forecastle.App{
+				{Name: "a", Group: "team-a-prod", GroupDisplayName: "team-a-prod", GroupFromNamespace: true,
+					Origin: &forecastle.Origin{Namespace: "team-a-prod"}},
+				{Name: "b", Group: "tools", GroupDisplayName: "tools",
+					Origin: &forecastle.Origin{Namespace: "team-b-prod"}},
+				{Name: "c", Group: "monitoring", GroupDisplayName: "monitoring", GroupFromNamespace: true,
+					Origin: &forecastle.Origin{Namespace: "monitoring"}},
+			},
+			appConfig: config.Config{GroupHierarchy: config.GroupHierarchy{NamespaceRules: []config.NamespaceGroupRule{
+				{Namespace: "[", Parent: "invalid"},
+				{Namespace: "^team-([a-z]+)-", Parent: "Teams/$1"},
+				{Namespace: "^team-", Parent: "Other"},
+			}}},
+			want: []forecastle.App{
+				{Name: "a", Group: "teams/a/team-a-prod", GroupDisplayName: "Teams/a/team-a-prod", ParentGroup: "teams/a",
+					GroupFromNamespace: true, Origin: &forecastle.Origin{Namespace: "team-a-prod"}},
+				{Name: "b", Group: "tools", GroupDisplayName: "tools",
+					Origin: &forecastle.Origin{Namespace: "team-b-prod"}},
+				{Name: "c", Group: "monitoring", GroupDisplayName: "monitoring",
+					GroupFromNamespace: true, Origin: &forecastle.Origin{Namespace: "monitoring"}},
+			},
+		},
+		{
+			name: "ConfiguredDisplayName",
+			apps: []forecastle.App{
+				{Name: "a", Group: "platform/observability", GroupDisplayName: "Platform/Observability"},
+			},
+			appConfig: config.Config{Groups: map[string]config.Group{
+				"platform/observability": {DisplayName: "Observability & Alerting"},
+			}},
+			want: []forecastle.App{
+				{Name: "a", Group: "platform/observability", GroupDisplayName: "Observability & Alerting", ParentGroup: "platform"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Apply(tt.apps, tt.appConfig)
+			if !reflect.DeepEqual(tt.apps, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", tt.apps, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree(t *testing.T) {
+	apps := []forecastle.App{
+		{Name: "grafana", Group: "platform/observability", GroupDisplayName: "Platform/Observability", ParentGroup: "platform"},
+		{Name: "vault", Group: "platform", GroupDisplayName: "Platform"},
+		{Name: "argocd", Group: "dev", GroupDisplayName: "Dev"},
+		{Name: "loki", Group: "platform/observability", GroupDisplayName: "Logs & Metrics", ParentGroup: "platform"},
+	}
+	appConfig := config.Config{Groups: map[string]config.Group{
+		"platform":               {Order: -1, Icon: "https://example.com/platform.png"},
+		"platform/observability": {DisplayName: "Logs & Metrics", Collapsed: true},
+	}}
+
+	want := []*Node{
+		{
+			Slug:  "platform",
+			Name:  "Platform",
+			Icon:  "https://example.com/platform.png",
+			Order: -1,
+			Apps:  []forecastle.App{apps[1]},
+			Children: []*Node{
+				{
+					Slug:      "platform/observability",
+					Name:      "Logs & Metrics",
+					Collapsed: true,
+					Apps:      []forecastle.App{apps[0], apps[3]},
+				},
+			},
+		},
+		{
+			Slug: "dev",
+			Name: "Dev",
+			Apps: []forecastle.App{apps[2]},
+		},
+	}
+
+	got := Tree(apps, appConfig)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tree() = %+v, want %+v", got, want)
+	}
+}
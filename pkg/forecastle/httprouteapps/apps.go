@@ -1,14 +1,20 @@
 package httprouteapps
 
 import (
+	"context"
+
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/derivation"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle/filters"
 	"github.com/stakater/Forecastle/v1/pkg/kube/lists/httproutes"
 	"github.com/stakater/Forecastle/v1/pkg/kube/wrappers"
 	"github.com/stakater/Forecastle/v1/pkg/log"
-	"github.com/stakater/Forecastle/v1/pkg/util/strings"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 )
@@ -21,6 +27,8 @@ type List struct {
 	err           error
 	items         []forecastle.App
 	gatewayClient gateway.Interface
+	namespaces    map[string]*corev1.Namespace
+	detector      access.Detector
 }
 
 // NewList creates a new instance of apps lister for HTTPRoutes
@@ -31,6 +39,18 @@ func NewList(gatewayClient gateway.Interface, appConfig config.Config) *List {
 	}
 }
 
+// WithNamespaces sets the namespace objects whose forecastle annotations and labels act as defaults
+func (al *List) WithNamespaces(namespaces map[string]*corev1.Namespace) *List {
+	al.namespaces = namespaces
+	return al
+}
+
+// WithAccessDetector sets the detector of access restrictions from the Traefik middlewares of HTTPRoutes
+func (al *List) WithAccessDetector(detector access.Detector) *List {
+	al.detector = detector
+	return al
+}
+
 // Populate populates a list of forecastle apps from HTTPRoutes in selected namespaces
 func (al *List) Populate(namespaces ...string) *List {
 	if al.gatewayClient == nil {
@@ -42,19 +62,37 @@ func (al *List) Populate(namespaces ...string) *List {
 		Filter(func(hr gatewayv1.HTTPRoute, cfg config.Config) bool {
 			return filters.ByForecastleExposeAnnotation(hr.Annotations, cfg)
 		}).Get()
+	if err != nil {
+		al.err = err
+		return al
+	}
 
 	if len(al.appConfig.InstanceName) != 0 {
 		httpRouteList, err = httproutes.NewList(al.gatewayClient, al.appConfig, httpRouteList...).
 			Filter(func(hr gatewayv1.HTTPRoute, cfg config.Config) bool {
-				return filters.ByForecastleInstanceAnnotation(hr.Annotations, cfg)
+				wrapper := wrappers.NewHTTPRouteWrapper(&hr).WithNamespace(al.namespaces[hr.Namespace])
+				return filters.ByInstance(wrapper.GetAnnotationValue(annotations.ForecastleInstanceAnnotation), cfg)
 			}).Get()
+		if err != nil {
+			al.err = err
+			return al
+		}
 	}
 
-	if err != nil {
-		al.err = err
+	// Apply GatewayClass filter through the parent Gateways of each HTTPRoute
+	if len(al.appConfig.GatewayClasses) != 0 {
+		gatewayClassNames := map[types.NamespacedName]string{}
+		httpRouteList, err = httproutes.NewList(al.gatewayClient, al.appConfig, httpRouteList...).
+			Filter(func(hr gatewayv1.HTTPRoute, cfg config.Config) bool {
+				return filters.ByGatewayClass(al.getParentGatewayClassNames(&hr, gatewayClassNames), cfg)
+			}).Get()
+		if err != nil {
+			al.err = err
+			return al
+		}
 	}
 
-	al.items = convertHTTPRoutesToForecastleApps(httpRouteList)
+	al.items = convertHTTPRoutesToForecastleApps(httpRouteList, al.namespaces, derivation.NewRules(al.appConfig.Derivation), al.appConfig.RecommendedLabels, al.detector)
 
 	return al
 }
@@ -64,19 +102,70 @@ func (al *List) Get() ([]forecastle.App, error) {
 	return al.items, al.err
 }
 
-func convertHTTPRoutesToForecastleApps(httpRoutes []gatewayv1.HTTPRoute) (apps []forecastle.App) {
+// getParentGatewayClassNames resolves the gatewayClassName of every parent Gateway of the HTTPRoute.
+// Lookups are memoized in cache so that Gateways shared by many routes are fetched only once
+func (al *List) getParentGatewayClassNames(httpRoute *gatewayv1.HTTPRoute, cache map[types.NamespacedName]string) []string {
+	var gatewayClassNames []string
+	for _, gatewayRef := range wrappers.NewHTTPRouteWrapper(httpRoute).GetParentGateways() {
+		gatewayClassName, ok := cache[gatewayRef]
+		if !ok {
+			gateway, err := al.gatewayClient.GatewayV1().Gateways(gatewayRef.Namespace).Get(context.TODO(), gatewayRef.Name, metav1.GetOptions{})
+			if err != nil {
+				logger.Warnf("Unable to get parent Gateway '%v' of HTTPRoute '%v': %v", gatewayRef, httpRoute.Name, err)
+			} else {
+				gatewayClassName = string(gateway.Spec.GatewayClassName)
+			}
+			cache[gatewayRef] = gatewayClassName
+		}
+		if gatewayClassName != "" {
+			gatewayClassNames = append(gatewayClassNames, gatewayClassName)
+		}
+	}
+	return gatewayClassNames
+}
+
+func convertHTTPRoutesToForecastleApps(httpRoutes []gatewayv1.HTTPRoute, namespaces map[string]*corev1.Namespace, rules *derivation.Rules, recommendedLabels bool, detector access.Detector) (apps []forecastle.App) {
 	for _, httpRoute := range httpRoutes {
 		logger.Infof("Found HTTPRoute with Name '%v' in Namespace '%v'", httpRoute.Name, httpRoute.Namespace)
 
-		wrapper := wrappers.NewHTTPRouteWrapper(&httpRoute)
+		wrapper := wrappers.NewHTTPRouteWrapper(&httpRoute).WithNamespace(namespaces[httpRoute.Namespace]).WithDerivationRules(rules).WithRecommendedLabels(recommendedLabels)
+		url, err := wrapper.ResolveURL()
+		if err != nil {
+			logger.Warnf("Skipping... HTTPRoute with Name '%v' in Namespace '%v' has no usable URL: %v", httpRoute.Name, httpRoute.Namespace, err)
+			continue
+		}
+		detected := access.Detect(detector, access.Object{
+			Kind:        "HTTPRoute",
+			Namespace:   httpRoute.Namespace,
+			Name:        httpRoute.Name,
+			Annotations: httpRoute.Annotations,
+			Middlewares: wrapper.GetMiddlewares(),
+		}).WithOverrides(
+			wrapper.GetAnnotationValue(annotations.ForecastleNetworkRestrictedAnnotation),
+			wrapper.GetAnnotationValue(annotations.ForecastleRequiresAuthAnnotation),
+		)
 		apps = append(apps, forecastle.App{
-			Name:              wrapper.GetName(),
-			Group:             wrapper.GetGroup(),
-			Icon:              wrapper.GetAnnotationValue(annotations.ForecastleIconAnnotation),
-			URL:               wrapper.GetURL(),
-			DiscoverySource:   forecastle.HTTPRoute,
-			NetworkRestricted: strings.ParseBool(wrapper.GetAnnotationValue(annotations.ForecastleNetworkRestrictedAnnotation)),
-			Properties:        wrapper.GetProperties(),
+			Name:               wrapper.GetName(),
+			Group:              wrapper.GetGroup(),
+			GroupDisplayName:   wrapper.GetGroupDisplayName(),
+			Icon:               wrapper.GetAnnotationValue(annotations.ForecastleIconAnnotation),
+			URL:                url,
+			Description:        wrapper.GetAnnotationValue(annotations.ForecastleDescriptionAnnotation),
+			Tags:               wrapper.GetTags(),
+			Weight:             wrapper.GetWeight(),
+			Links:              wrapper.GetLinks(),
+			Version:            wrapper.GetRecommendedLabel(annotations.AppVersionLabel),
+			Component:          wrapper.GetRecommendedLabel(annotations.AppComponentLabel),
+			ManagedBy:          wrapper.GetRecommendedLabel(annotations.AppManagedByLabel),
+			AppID:              wrapper.GetAnnotationValue(annotations.ForecastleAppIDAnnotation),
+			DiscoverySource:    forecastle.HTTPRoute,
+			NetworkRestricted:  detected.NetworkRestricted,
+			RequiresAuth:       detected.RequiresAuth,
+			AllowedGroups:      wrapper.GetAllowedGroups(),
+			Properties:         wrapper.GetProperties(),
+			Origin:             forecastle.NewOrigin("HTTPRoute", httpRoute.ObjectMeta),
+			GroupFromNamespace: wrapper.IsGroupFromNamespace(),
+			Annotations:        annotations.ForecastleAnnotations(httpRoute.Annotations),
 		})
 	}
 	return
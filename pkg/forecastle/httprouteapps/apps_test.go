@@ -2,16 +2,22 @@ package httprouteapps
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
 	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	traefikv1alpha1 "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
@@ -153,11 +159,14 @@ func Test_convertHTTPRoutesToForecastleApps(t *testing.T) {
 			},
 			wantApps: []forecastle.App{
 				{
-					Name:            "test-route",
-					Group:           "",
-					Icon:            "https://example.com/icon.png",
-					URL:             "https://app.example.com",
-					DiscoverySource: forecastle.HTTPRoute,
+					Name:               "test-route",
+					Group:              "",
+					Icon:               "https://example.com/icon.png",
+					URL:                "https://app.example.com",
+					DiscoverySource:    forecastle.HTTPRoute,
+					Origin:             &forecastle.Origin{Kind: "HTTPRoute", Name: "test-route"},
+					Annotations:        map[string]string{annotations.ForecastleIconAnnotation: "https://example.com/icon.png"},
+					GroupFromNamespace: true,
 				},
 			},
 		},
@@ -165,7 +174,7 @@ func Test_convertHTTPRoutesToForecastleApps(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
-				if gotApps := convertHTTPRoutesToForecastleApps(tt.args.httpRoutes); !reflect.DeepEqual(gotApps, tt.wantApps) {
+				if gotApps := convertHTTPRoutesToForecastleApps(tt.args.httpRoutes, nil, nil, false, nil); !reflect.DeepEqual(gotApps, tt.wantApps) {
 					t.Errorf("convertHTTPRoutesToForecastleApps() = %v, want %v", gotApps, tt.wantApps)
 				}
 			},
@@ -173,6 +182,32 @@ func Test_convertHTTPRoutesToForecastleApps(t *testing.T) {
 	}
 }
 
+func Test_convertHTTPRoutesToForecastleAppsWithAccessDetector(t *testing.T) {
+	detector := access.NewTraefikDetectorWithGetter(func(namespace string, name string) (*traefikv1alpha1.Middleware, error) {
+		return &traefikv1alpha1.Middleware{Spec: traefikv1alpha1.MiddlewareSpec{IPAllowList: &dynamic.IPAllowList{}}}, nil
+	})
+	middlewareFilter := gatewayv1.HTTPRouteFilter{
+		Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: &gatewayv1.LocalObjectReference{Group: "traefik.io", Kind: "Middleware", Name: "office-only"},
+	}
+
+	restricted := testutil.CreateHTTPRouteWithHostname("restricted", "restricted.example.com")
+	restricted.Spec.Rules = []gatewayv1.HTTPRouteRule{{Filters: []gatewayv1.HTTPRouteFilter{middlewareFilter}}}
+	overridden := testutil.AddAnnotationToHTTPRoute(testutil.CreateHTTPRouteWithHostname("overridden", "overridden.example.com"),
+		annotations.ForecastleNetworkRestrictedAnnotation, "false")
+	overridden.Spec.Rules = []gatewayv1.HTTPRouteRule{{Filters: []gatewayv1.HTTPRouteFilter{middlewareFilter}}}
+	unfiltered := testutil.CreateHTTPRouteWithHostname("unfiltered", "unfiltered.example.com")
+
+	apps := convertHTTPRoutesToForecastleApps([]gatewayv1.HTTPRoute{*restricted, *overridden, *unfiltered}, nil, nil, false, detector)
+	var got []bool
+	for _, app := range apps {
+		got = append(got, app.NetworkRestricted)
+	}
+	if want := []bool{true, false, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("convertHTTPRoutesToForecastleApps() NetworkRestricted = %v, want %v", got, want)
+	}
+}
+
 func TestList_Populate(t *testing.T) {
 	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
 	gatewayClient := gatewayfake.NewSimpleClientset()
@@ -185,6 +220,11 @@ func TestList_Populate(t *testing.T) {
 		annotations.ForecastleNetworkRestrictedAnnotation, "true",
 	)
 
+	httpRouteAnnotations := map[string]string{
+		annotations.ForecastleExposeAnnotation:            "true",
+		annotations.ForecastleNetworkRestrictedAnnotation: "true",
+	}
+
 	_, _ = kubeClient.CoreV1().Namespaces().Create(
 		context.TODO(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "testing"}}, metav1.CreateOptions{},
 	)
@@ -225,18 +265,26 @@ func TestList_Populate(t *testing.T) {
 				gatewayClient: gatewayClient,
 				items: []forecastle.App{
 					{
-						Name:              "test-route",
-						Group:             "default",
-						URL:               "https://app.example.com",
-						DiscoverySource:   forecastle.HTTPRoute,
-						NetworkRestricted: true,
+						Name:               "test-route",
+						Group:              "default",
+						GroupDisplayName:   "default",
+						URL:                "https://app.example.com",
+						DiscoverySource:    forecastle.HTTPRoute,
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "HTTPRoute", Namespace: "default", Name: "test-route"},
+						Annotations:        httpRouteAnnotations,
+						GroupFromNamespace: true,
 					},
 					{
-						Name:              "test-route",
-						Group:             "testing",
-						URL:               "https://app.example.com",
-						DiscoverySource:   forecastle.HTTPRoute,
-						NetworkRestricted: true,
+						Name:               "test-route",
+						Group:              "testing",
+						GroupDisplayName:   "testing",
+						URL:                "https://app.example.com",
+						DiscoverySource:    forecastle.HTTPRoute,
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "HTTPRoute", Namespace: "testing", Name: "test-route"},
+						Annotations:        httpRouteAnnotations,
+						GroupFromNamespace: true,
 					},
 				},
 			},
@@ -253,11 +301,15 @@ func TestList_Populate(t *testing.T) {
 				gatewayClient: gatewayClient,
 				items: []forecastle.App{
 					{
-						Name:              "test-route",
-						Group:             "testing",
-						URL:               "https://app.example.com",
-						DiscoverySource:   forecastle.HTTPRoute,
-						NetworkRestricted: true,
+						Name:               "test-route",
+						Group:              "testing",
+						GroupDisplayName:   "testing",
+						URL:                "https://app.example.com",
+						DiscoverySource:    forecastle.HTTPRoute,
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "HTTPRoute", Namespace: "testing", Name: "test-route"},
+						Annotations:        httpRouteAnnotations,
+						GroupFromNamespace: true,
 					},
 				},
 			},
@@ -294,3 +346,58 @@ func TestList_Populate(t *testing.T) {
 	_ = gatewayClient.GatewayV1().HTTPRoutes("default").Delete(context.TODO(), "test-route", metav1.DeleteOptions{})
 	_ = gatewayClient.GatewayV1().HTTPRoutes("testing").Delete(context.TODO(), "test-route", metav1.DeleteOptions{})
 }
+
+func TestList_PopulateWithGatewayClasses(t *testing.T) {
+	gatewayClient := gatewayfake.NewSimpleClientset()
+
+	_, _ = gatewayClient.GatewayV1().Gateways("infra").Create(context.TODO(), &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal-gw", Namespace: "infra"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "internal"},
+	}, metav1.CreateOptions{})
+	_, _ = gatewayClient.GatewayV1().Gateways("infra").Create(context.TODO(), &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public-gw", Namespace: "infra"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "public"},
+	}, metav1.CreateOptions{})
+
+	infra := gatewayv1.Namespace("infra")
+	routes := map[string][]gatewayv1.ParentReference{
+		"internal-route": {{Name: "internal-gw", Namespace: &infra}},
+		"public-route":   {{Name: "public-gw", Namespace: &infra}},
+		"both-route":     {{Name: "public-gw", Namespace: &infra}, {Name: "internal-gw", Namespace: &infra}},
+		"missing-route":  {{Name: "missing-gw", Namespace: &infra}},
+		"orphan-route":   nil,
+	}
+	for name, parentRefs := range routes {
+		httpRoute := testutil.AddAnnotationToHTTPRoute(
+			testutil.CreateHTTPRouteWithHostnameAndNamespace(name, "default", name+".example.com"),
+			annotations.ForecastleExposeAnnotation, "true")
+		httpRoute.Spec.ParentRefs = parentRefs
+		_, _ = gatewayClient.GatewayV1().HTTPRoutes("default").Create(context.TODO(), httpRoute, metav1.CreateOptions{})
+	}
+
+	apps, err := NewList(gatewayClient, config.Config{GatewayClasses: []string{"internal"}}).Populate("default").Get()
+	if err != nil {
+		t.Fatalf("List.Populate() error = %v", err)
+	}
+
+	var got []string
+	for _, app := range apps {
+		got = append(got, app.Name)
+	}
+	want := []string{"both-route", "internal-route"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List.Populate() names = %v, want %v", got, want)
+	}
+}
+
+func TestList_PopulateKeepsListError(t *testing.T) {
+	gatewayClient := gatewayfake.NewSimpleClientset()
+	gatewayClient.PrependReactor("list", "httproutes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("list failed")
+	})
+
+	appConfig := config.Config{InstanceName: "internal", GatewayClasses: []string{"internal"}}
+	if _, err := NewList(gatewayClient, appConfig).Populate("default").Get(); err == nil {
+		t.Error("List.Populate() error = nil, want the error of listing HTTPRoutes")
+	}
+}
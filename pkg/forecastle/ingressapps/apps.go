@@ -4,11 +4,14 @@ import (
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/derivation"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle/filters"
 	"github.com/stakater/Forecastle/v1/pkg/kube/lists/ingresses"
+	"github.com/stakater/Forecastle/v1/pkg/kube/util"
 	"github.com/stakater/Forecastle/v1/pkg/kube/wrappers"
 	"github.com/stakater/Forecastle/v1/pkg/log"
-	"github.com/stakater/Forecastle/v1/pkg/util/strings"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -23,6 +26,8 @@ type List struct {
 	err        error // Used for forwarding errors
 	items      []forecastle.App
 	kubeClient kubernetes.Interface
+	namespaces map[string]*corev1.Namespace
+	detector   access.Detector
 }
 
 // NewList func creates a new instance of apps lister
@@ -33,6 +38,18 @@ func NewList(kubeClient kubernetes.Interface, appConfig config.Config) *List {
 	}
 }
 
+// WithNamespaces function sets the namespace objects whose forecastle annotations and labels act as defaults
+func (al *List) WithNamespaces(namespaces map[string]*corev1.Namespace) *List {
+	al.namespaces = namespaces
+	return al
+}
+
+// WithAccessDetector function sets the detector of access restrictions from ingress controller annotations
+func (al *List) WithAccessDetector(detector access.Detector) *List {
+	al.detector = detector
+	return al
+}
+
 // Populate function that populates a list of forecastle apps from ingresses in selected namespaces
 func (al *List) Populate(namespaces ...string) *List {
 	ingressList, err := ingresses.NewList(al.kubeClient, al.appConfig).
@@ -40,20 +57,46 @@ func (al *List) Populate(namespaces ...string) *List {
 		Filter(func(ing v1.Ingress, cfg config.Config) bool {
 			return filters.ByForecastleExposeAnnotation(ing.Annotations, cfg)
 		}).Get()
+	if err != nil {
+		al.err = err
+		return al
+	}
 
 	// Apply Instance filter
 	if len(al.appConfig.InstanceName) != 0 {
 		ingressList, err = ingresses.NewList(al.kubeClient, al.appConfig, ingressList...).
 			Filter(func(ing v1.Ingress, cfg config.Config) bool {
-				return filters.ByForecastleInstanceAnnotation(ing.Annotations, cfg)
+				wrapper := wrappers.NewIngressWrapper(&ing).WithNamespace(al.namespaces[ing.Namespace])
+				return filters.ByInstance(wrapper.GetAnnotationValue(annotations.ForecastleInstanceAnnotation), cfg)
 			}).Get()
+		if err != nil {
+			al.err = err
+			return al
+		}
 	}
 
-	if err != nil {
-		al.err = err
+	// Apply IngressClass filter
+	if len(al.appConfig.IngressClasses) != 0 {
+		defaultIngressClassName, classErr := util.GetDefaultIngressClassName(al.kubeClient)
+		if classErr != nil {
+			logger.Warnf("Unable to look up default IngressClass: %v", classErr)
+		}
+
+		ingressList, err = ingresses.NewList(al.kubeClient, al.appConfig, ingressList...).
+			Filter(func(ing v1.Ingress, cfg config.Config) bool {
+				ingressClassName := wrappers.NewIngressWrapper(&ing).GetIngressClassName()
+				if ingressClassName == "" {
+					ingressClassName = defaultIngressClassName
+				}
+				return filters.ByIngressClass(ingressClassName, cfg)
+			}).Get()
+		if err != nil {
+			al.err = err
+			return al
+		}
 	}
 
-	al.items = convertIngressesToForecastleApps(ingressList)
+	al.items = convertIngressesToForecastleApps(ingressList, al.namespaces, derivation.NewRules(al.appConfig.Derivation), al.appConfig.RecommendedLabels, al.detector)
 
 	return al
 }
@@ -63,19 +106,47 @@ func (al *List) Get() ([]forecastle.App, error) {
 	return al.items, al.err
 }
 
-func convertIngressesToForecastleApps(ingresses []v1.Ingress) (apps []forecastle.App) {
+func convertIngressesToForecastleApps(ingresses []v1.Ingress, namespaces map[string]*corev1.Namespace, rules *derivation.Rules, recommendedLabels bool, detector access.Detector) (apps []forecastle.App) {
 	for _, ingress := range ingresses {
 		logger.Infof("Found ingress with Name '%v' in Namespace '%v'", ingress.Name, ingress.Namespace)
 
-		wrapper := wrappers.NewIngressWrapper(&ingress)
+		wrapper := wrappers.NewIngressWrapper(&ingress).WithNamespace(namespaces[ingress.Namespace]).WithDerivationRules(rules).WithRecommendedLabels(recommendedLabels)
+		url, err := wrapper.ResolveURL()
+		if err != nil {
+			logger.Warnf("Skipping... ingress with Name '%v' in Namespace '%v' has no usable URL: %v", ingress.Name, ingress.Namespace, err)
+			continue
+		}
+		detected := access.Detect(detector, access.Object{
+			Kind:        "Ingress",
+			Namespace:   ingress.Namespace,
+			Name:        ingress.Name,
+			Annotations: ingress.Annotations,
+		}).WithOverrides(
+			wrapper.GetAnnotationValue(annotations.ForecastleNetworkRestrictedAnnotation),
+			wrapper.GetAnnotationValue(annotations.ForecastleRequiresAuthAnnotation),
+		)
 		apps = append(apps, forecastle.App{
-			Name:              wrapper.GetName(),
-			Group:             wrapper.GetGroup(),
-			Icon:              wrapper.GetAnnotationValue(annotations.ForecastleIconAnnotation),
-			URL:               wrapper.GetURL(),
-			DiscoverySource:   forecastle.Ingress,
-			NetworkRestricted: strings.ParseBool(wrapper.GetAnnotationValue(annotations.ForecastleNetworkRestrictedAnnotation)),
-			Properties:        wrapper.GetProperties(),
+			Name:               wrapper.GetName(),
+			Group:              wrapper.GetGroup(),
+			GroupDisplayName:   wrapper.GetGroupDisplayName(),
+			Icon:               wrapper.GetAnnotationValue(annotations.ForecastleIconAnnotation),
+			URL:                url,
+			Description:        wrapper.GetAnnotationValue(annotations.ForecastleDescriptionAnnotation),
+			Tags:               wrapper.GetTags(),
+			Weight:             wrapper.GetWeight(),
+			Links:              wrapper.GetLinks(),
+			Version:            wrapper.GetRecommendedLabel(annotations.AppVersionLabel),
+			Component:          wrapper.GetRecommendedLabel(annotations.AppComponentLabel),
+			ManagedBy:          wrapper.GetRecommendedLabel(annotations.AppManagedByLabel),
+			AppID:              wrapper.GetAnnotationValue(annotations.ForecastleAppIDAnnotation),
+			DiscoverySource:    forecastle.Ingress,
+			NetworkRestricted:  detected.NetworkRestricted,
+			RequiresAuth:       detected.RequiresAuth,
+			AllowedGroups:      wrapper.GetAllowedGroups(),
+			Properties:         wrapper.GetProperties(),
+			Origin:             forecastle.NewOrigin("Ingress", ingress.ObjectMeta),
+			GroupFromNamespace: wrapper.IsGroupFromNamespace(),
+			Annotations:        annotations.ForecastleAnnotations(ingress.Annotations),
 		})
 	}
 	return
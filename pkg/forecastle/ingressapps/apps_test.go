@@ -2,18 +2,22 @@ package ingressapps
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/config"
 	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/access"
 	"github.com/stakater/Forecastle/v1/pkg/testutil"
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestNewList(t *testing.T) {
@@ -146,23 +150,49 @@ func Test_convertIngressesToForecastleApps(t *testing.T) {
 			},
 			wantApps: []forecastle.App{
 				{
-					Name:  "test-ingress",
-					Group: "",
-					Icon:  "https://google.com/icon.png",
-					URL:   "http://google.com",
+					Name:               "test-ingress",
+					Group:              "",
+					Icon:               "https://google.com/icon.png",
+					URL:                "http://google.com",
+					Origin:             &forecastle.Origin{Kind: "Ingress", Name: "test-ingress"},
+					Annotations:        map[string]string{annotations.ForecastleIconAnnotation: "https://google.com/icon.png"},
+					GroupFromNamespace: true,
 				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotApps := convertIngressesToForecastleApps(tt.args.ingresses); !reflect.DeepEqual(gotApps, tt.wantApps) {
+			if gotApps := convertIngressesToForecastleApps(tt.args.ingresses, nil, nil, false, nil); !reflect.DeepEqual(gotApps, tt.wantApps) {
 				t.Errorf("convertIngressesToForecastleApps() = %v, want %v", gotApps, tt.wantApps)
 			}
 		})
 	}
 }
 
+func Test_convertIngressesToForecastleAppsWithAccessDetector(t *testing.T) {
+	restricted := testutil.AddAnnotationToIngress(testutil.CreateIngressWithHost("restricted", "restricted.example.com"),
+		"nginx.ingress.kubernetes.io/whitelist-source-range", "10.0.0.0/8")
+	testutil.AddAnnotationToIngress(restricted, "nginx.ingress.kubernetes.io/auth-url", "https://auth.example.com/oauth2/auth")
+
+	overridden := testutil.AddAnnotationToIngress(testutil.CreateIngressWithHost("overridden", "overridden.example.com"),
+		"nginx.ingress.kubernetes.io/whitelist-source-range", "10.0.0.0/8")
+	testutil.AddAnnotationToIngress(overridden, annotations.ForecastleNetworkRestrictedAnnotation, "false")
+	testutil.AddAnnotationToIngress(overridden, annotations.ForecastleRequiresAuthAnnotation, "true")
+
+	apps := convertIngressesToForecastleApps([]networking.Ingress{*restricted, *overridden}, nil, nil, false, access.NginxDetector{})
+	if len(apps) != 2 {
+		t.Fatalf("convertIngressesToForecastleApps() returned %d apps, want 2", len(apps))
+	}
+	if !apps[0].NetworkRestricted || !apps[0].RequiresAuth {
+		t.Errorf("app %q: NetworkRestricted = %v, RequiresAuth = %v, want both detected", apps[0].Name, apps[0].NetworkRestricted, apps[0].RequiresAuth)
+	}
+	if apps[1].NetworkRestricted || !apps[1].RequiresAuth {
+		t.Errorf("app %q: NetworkRestricted = %v, RequiresAuth = %v, want the annotations to override detection",
+			apps[1].Name, apps[1].NetworkRestricted, apps[1].RequiresAuth)
+	}
+}
+
 func TestList_Populate(t *testing.T) {
 	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
 
@@ -171,6 +201,11 @@ func TestList_Populate(t *testing.T) {
 			testutil.CreateIngressWithHost("test-ingress", "google.com"), annotations.ForecastleExposeAnnotation, "true"),
 		annotations.ForecastleNetworkRestrictedAnnotation, "true")
 
+	ingressAnnotations := map[string]string{
+		annotations.ForecastleExposeAnnotation:            "true",
+		annotations.ForecastleNetworkRestrictedAnnotation: "true",
+	}
+
 	_, _ = kubeClient.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "testing"}}, metav1.CreateOptions{})
 	_, _ = kubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingress, metav1.CreateOptions{})
 	_, _ = kubeClient.NetworkingV1().Ingresses("testing").Create(context.TODO(), ingress, metav1.CreateOptions{})
@@ -201,16 +236,24 @@ func TestList_Populate(t *testing.T) {
 				kubeClient: kubeClient,
 				items: []forecastle.App{
 					{
-						Name:              "test-ingress",
-						Group:             "default",
-						URL:               "http://google.com",
-						NetworkRestricted: true,
+						Name:               "test-ingress",
+						Group:              "default",
+						GroupDisplayName:   "default",
+						URL:                "http://google.com",
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "Ingress", Namespace: "default", Name: "test-ingress"},
+						Annotations:        ingressAnnotations,
+						GroupFromNamespace: true,
 					},
 					{
-						Name:              "test-ingress",
-						Group:             "testing",
-						URL:               "http://google.com",
-						NetworkRestricted: true,
+						Name:               "test-ingress",
+						Group:              "testing",
+						GroupDisplayName:   "testing",
+						URL:                "http://google.com",
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "Ingress", Namespace: "testing", Name: "test-ingress"},
+						Annotations:        ingressAnnotations,
+						GroupFromNamespace: true,
 					},
 				},
 			},
@@ -227,16 +270,24 @@ func TestList_Populate(t *testing.T) {
 				kubeClient: kubeClient,
 				items: []forecastle.App{
 					{
-						Name:              "test-ingress",
-						Group:             "default",
-						URL:               "http://google.com",
-						NetworkRestricted: true,
+						Name:               "test-ingress",
+						Group:              "default",
+						GroupDisplayName:   "default",
+						URL:                "http://google.com",
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "Ingress", Namespace: "default", Name: "test-ingress"},
+						Annotations:        ingressAnnotations,
+						GroupFromNamespace: true,
 					},
 					{
-						Name:              "test-ingress",
-						Group:             "testing",
-						URL:               "http://google.com",
-						NetworkRestricted: true,
+						Name:               "test-ingress",
+						Group:              "testing",
+						GroupDisplayName:   "testing",
+						URL:                "http://google.com",
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "Ingress", Namespace: "testing", Name: "test-ingress"},
+						Annotations:        ingressAnnotations,
+						GroupFromNamespace: true,
 					},
 				},
 			},
@@ -253,10 +304,14 @@ func TestList_Populate(t *testing.T) {
 				kubeClient: kubeClient,
 				items: []forecastle.App{
 					{
-						Name:              "test-ingress",
-						Group:             "testing",
-						URL:               "http://google.com",
-						NetworkRestricted: true,
+						Name:               "test-ingress",
+						Group:              "testing",
+						GroupDisplayName:   "testing",
+						URL:                "http://google.com",
+						NetworkRestricted:  true,
+						Origin:             &forecastle.Origin{Kind: "Ingress", Namespace: "testing", Name: "test-ingress"},
+						Annotations:        ingressAnnotations,
+						GroupFromNamespace: true,
 					},
 				},
 			},
@@ -279,3 +334,91 @@ func TestList_Populate(t *testing.T) {
 	_ = kubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "test-ingress", metav1.DeleteOptions{})
 	_ = kubeClient.NetworkingV1().Ingresses("testing").Delete(context.TODO(), "test-ingress", metav1.DeleteOptions{})
 }
+
+func TestList_PopulateWithIngressClasses(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+
+	internalClass := "internal"
+	publicClass := "public"
+
+	bySpec := testutil.AddAnnotationToIngress(
+		testutil.CreateIngressWithHost("by-spec", "spec.example.com"), annotations.ForecastleExposeAnnotation, "true")
+	bySpec.Spec.IngressClassName = &internalClass
+
+	byAnnotation := testutil.AddAnnotationToIngress(
+		testutil.AddAnnotationToIngress(
+			testutil.CreateIngressWithHost("by-annotation", "annotation.example.com"), annotations.ForecastleExposeAnnotation, "true"),
+		annotations.IngressClassAnnotation, "internal")
+
+	byDefault := testutil.AddAnnotationToIngress(
+		testutil.CreateIngressWithHost("by-default", "default.example.com"), annotations.ForecastleExposeAnnotation, "true")
+
+	otherClass := testutil.AddAnnotationToIngress(
+		testutil.CreateIngressWithHost("other-class", "other.example.com"), annotations.ForecastleExposeAnnotation, "true")
+	otherClass.Spec.IngressClassName = &publicClass
+
+	for _, ingress := range []*networking.Ingress{bySpec, byAnnotation, byDefault, otherClass} {
+		_, _ = kubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingress, metav1.CreateOptions{})
+	}
+
+	tests := []struct {
+		name         string
+		defaultClass string
+		want         []string
+	}{
+		{
+			name: "WithoutDefaultIngressClass",
+			want: []string{"by-annotation", "by-spec"},
+		},
+		{
+			name:         "WithInternalAsDefaultIngressClass",
+			defaultClass: internalClass,
+			want:         []string{"by-annotation", "by-default", "by-spec"},
+		},
+		{
+			name:         "WithPublicAsDefaultIngressClass",
+			defaultClass: publicClass,
+			want:         []string{"by-annotation", "by-spec"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.defaultClass != "" {
+				_, _ = kubeClient.NetworkingV1().IngressClasses().Create(context.TODO(), &networking.IngressClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        tt.defaultClass,
+						Annotations: map[string]string{networking.AnnotationIsDefaultIngressClass: "true"},
+					},
+				}, metav1.CreateOptions{})
+				defer func() {
+					_ = kubeClient.NetworkingV1().IngressClasses().Delete(context.TODO(), tt.defaultClass, metav1.DeleteOptions{})
+				}()
+			}
+
+			apps, err := NewList(kubeClient, config.Config{IngressClasses: []string{internalClass}}).Populate("default").Get()
+			if err != nil {
+				t.Fatalf("List.Populate() error = %v", err)
+			}
+
+			var got []string
+			for _, app := range apps {
+				got = append(got, app.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List.Populate() names = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList_PopulateKeepsListError(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	kubeClient.PrependReactor("list", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("list failed")
+	})
+
+	appConfig := config.Config{InstanceName: "internal", IngressClasses: []string{"internal"}}
+	if _, err := NewList(kubeClient, appConfig).Populate("default").Get(); err == nil {
+		t.Error("List.Populate() error = nil, want the error of listing ingresses")
+	}
+}
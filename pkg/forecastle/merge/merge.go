@@ -0,0 +1,199 @@
+package merge
+
+import (
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+)
+
+var (
+	logger = log.New()
+)
+
+// DefaultSourcePrecedence is used when no source precedence is configured. Explicitly declared apps
+// win over apps discovered from routing resources
+var DefaultSourcePrecedence = []forecastle.DiscoverySource{
+	forecastle.Config,
+	forecastle.ForecastleAppCRD,
+	forecastle.HTTPRoute,
+	forecastle.Ingress,
+}
+
+// Apps merges apps that were discovered through multiple sources into a single app. Apps are keyed on their
+// app id, or else on their normalized URL; apps with neither are never merged. The order of the first
+// occurrence of every app is preserved
+func Apps(apps []forecastle.App, appConfig config.Config) []forecastle.App {
+	precedence := sourcePrecedence(appConfig.Deduplication.SourcePrecedence)
+
+	var keys []string
+	duplicates := map[string][]forecastle.App{}
+	var merged []forecastle.App
+
+	for i, app := range apps {
+		key := mergeKey(app)
+		if key == "" {
+			// Apps without a key are unique, keyed on their position
+			key = "index:" + strconv.Itoa(i)
+		}
+		if _, ok := duplicates[key]; !ok {
+			keys = append(keys, key)
+		}
+		duplicates[key] = append(duplicates[key], app)
+	}
+
+	for _, key := range keys {
+		merged = append(merged, mergeApps(duplicates[key], precedence))
+	}
+
+	return merged
+}
+
+func sourcePrecedence(configured []string) []forecastle.DiscoverySource {
+	if len(configured) == 0 {
+		return DefaultSourcePrecedence
+	}
+
+	var precedence []forecastle.DiscoverySource
+	for _, name := range configured {
+		source, err := forecastle.ParseDiscoverySource(name)
+		if err != nil {
+			logger.Warnf("Ignoring source precedence entry: %v", err)
+			continue
+		}
+		precedence = append(precedence, source)
+	}
+
+	// Sources missing from the configured precedence rank below all configured ones
+	for _, source := range DefaultSourcePrecedence {
+		if !slices.Contains(precedence, source) {
+			precedence = append(precedence, source)
+		}
+	}
+	return precedence
+}
+
+func mergeKey(app forecastle.App) string {
+	if app.AppID != "" {
+		return "id:" + app.AppID
+	}
+	if normalizedURL := normalizeURL(app.URL); normalizedURL != "" {
+		return "url:" + normalizedURL
+	}
+	return ""
+}
+
+// normalizeURL lowercases scheme and host, and drops default ports, fragments and trailing slashes
+// so that URLs pointing to the same page compare equal
+func normalizeURL(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Host == "" {
+		return ""
+	}
+
+	scheme := strings.ToLower(parsedURL.Scheme)
+	host := strings.ToLower(parsedURL.Hostname())
+	port := parsedURL.Port()
+	if port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+
+	normalized := scheme + "://" + host + strings.TrimSuffix(parsedURL.EscapedPath(), "/")
+	if parsedURL.RawQuery != "" {
+		normalized += "?" + parsedURL.RawQuery
+	}
+	return normalized
+}
+
+// mergeApps combines the duplicates of an app. For every field the value of the highest precedence
+// source that sets it wins, properties are merged key by key along with their types, tags and links are combined and an app is
+// network restricted if any of its sources says so. An app restricted to groups by any of its sources stays
+// restricted, to the groups allowed by any of them
+func mergeApps(apps []forecastle.App, precedence []forecastle.DiscoverySource) forecastle.App {
+	slices.SortStableFunc(apps, func(a, b forecastle.App) int {
+		return slices.Index(precedence, a.DiscoverySource) - slices.Index(precedence, b.DiscoverySource)
+	})
+
+	merged := apps[0]
+	merged.Properties = nil
+	merged.PropertyTypes = nil
+	merged.Tags = nil
+	merged.Links = nil
+	merged.AllowedGroups = nil
+	merged.DiscoverySources = nil
+
+	for i := len(apps) - 1; i >= 0; i-- {
+		app := apps[i]
+		if len(app.Properties) != 0 {
+			if merged.Properties == nil {
+				merged.Properties = map[string]string{}
+			}
+			maps.Copy(merged.Properties, app.Properties)
+		}
+		for key := range app.Properties {
+			if propertyType, ok := app.PropertyTypes[key]; ok {
+				if merged.PropertyTypes == nil {
+					merged.PropertyTypes = map[string]string{}
+				}
+				merged.PropertyTypes[key] = propertyType
+			} else {
+				delete(merged.PropertyTypes, key)
+			}
+		}
+	}
+
+	for _, app := range apps {
+		merged.Name = firstNonEmpty(merged.Name, app.Name)
+		merged.Icon = firstNonEmpty(merged.Icon, app.Icon)
+		if merged.Group == "" {
+			merged.Group, merged.GroupDisplayName = app.Group, app.GroupDisplayName
+		}
+		merged.URL = firstNonEmpty(merged.URL, app.URL)
+		merged.Description = firstNonEmpty(merged.Description, app.Description)
+		merged.Version = firstNonEmpty(merged.Version, app.Version)
+		merged.Component = firstNonEmpty(merged.Component, app.Component)
+		merged.ManagedBy = firstNonEmpty(merged.ManagedBy, app.ManagedBy)
+		if merged.Weight == 0 {
+			merged.Weight = app.Weight
+		}
+		for _, tag := range app.Tags {
+			if !merged.HasTag(tag) {
+				merged.Tags = append(merged.Tags, tag)
+			}
+		}
+		for _, link := range app.Links {
+			if !slices.ContainsFunc(merged.Links, func(l forecastle.Link) bool { return l.URL == link.URL }) {
+				merged.Links = append(merged.Links, link)
+			}
+		}
+		merged.AppID = firstNonEmpty(merged.AppID, app.AppID)
+		merged.NetworkRestricted = merged.NetworkRestricted || app.NetworkRestricted
+		merged.RequiresAuth = merged.RequiresAuth || app.RequiresAuth
+		for _, group := range app.AllowedGroups {
+			if !slices.Contains(merged.AllowedGroups, group) {
+				merged.AllowedGroups = append(merged.AllowedGroups, group)
+			}
+		}
+		if !slices.Contains(merged.DiscoverySources, app.DiscoverySource) {
+			merged.DiscoverySources = append(merged.DiscoverySources, app.DiscoverySource)
+		}
+	}
+
+	if len(apps) > 1 {
+		logger.Infof("Merged app '%v' discovered through %v", merged.Name, merged.DiscoverySources)
+	}
+
+	return merged
+}
+
+func firstNonEmpty(current string, candidate string) string {
+	if current != "" {
+		return current
+	}
+	return candidate
+}
@@ -0,0 +1,139 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+)
+
+func TestApps(t *testing.T) {
+	tests := []struct {
+		name      string
+		apps      []forecastle.App
+		appConfig config.Config
+		want      []forecastle.App
+	}{
+		{
+			name: "NoDuplicates",
+			apps: []forecastle.App{
+				{Name: "a", URL: "https://a.example.com", DiscoverySource: forecastle.Ingress},
+				{Name: "b", URL: "https://b.example.com", DiscoverySource: forecastle.Config},
+			},
+			want: []forecastle.App{
+				{Name: "a", URL: "https://a.example.com", DiscoverySource: forecastle.Ingress, DiscoverySources: []forecastle.DiscoverySource{forecastle.Ingress}},
+				{Name: "b", URL: "https://b.example.com", DiscoverySource: forecastle.Config, DiscoverySources: []forecastle.DiscoverySource{forecastle.Config}},
+			},
+		},
+		{
+			name: "DuplicatesByNormalizedURL",
+			apps: []forecastle.App{
+				{Name: "grafana-ingress", Group: "monitoring", URL: "https://Grafana.example.com:443/", DiscoverySource: forecastle.Ingress,
+					Properties: map[string]string{"Version": "9", "Owner": "ops"}},
+				{Name: "other", URL: "https://other.example.com", DiscoverySource: forecastle.Ingress},
+				{Name: "Grafana", Icon: "https://example.com/grafana.png", URL: "https://grafana.example.com", DiscoverySource: forecastle.ForecastleAppCRD,
+					Properties: map[string]string{"Version": "10"}},
+			},
+			want: []forecastle.App{
+				{Name: "Grafana", Icon: "https://example.com/grafana.png", Group: "monitoring", URL: "https://grafana.example.com",
+					DiscoverySource:  forecastle.ForecastleAppCRD,
+					DiscoverySources: []forecastle.DiscoverySource{forecastle.ForecastleAppCRD, forecastle.Ingress},
+					Properties:       map[string]string{"Version": "10", "Owner": "ops"}},
+				{Name: "other", URL: "https://other.example.com", DiscoverySource: forecastle.Ingress, DiscoverySources: []forecastle.DiscoverySource{forecastle.Ingress}},
+			},
+		},
+		{
+			name: "PropertyTypesFollowTheirProperties",
+			apps: []forecastle.App{
+				{Name: "grafana", URL: "https://grafana.example.com", DiscoverySource: forecastle.Ingress,
+					Properties: map[string]string{"Dashboard": "https://grafana/d/app", "Replicas": "3"}},
+				{Name: "Grafana", URL: "https://grafana.example.com", DiscoverySource: forecastle.ForecastleAppCRD,
+					Properties:    map[string]string{"Dashboard": "https://grafana/d/app", "Owner": "ops"},
+					PropertyTypes: map[string]string{"Dashboard": "url"}},
+				{Name: "grafana", URL: "https://grafana.example.com", DiscoverySource: forecastle.Config,
+					Properties: map[string]string{"Owner": "platform"}},
+			},
+			want: []forecastle.App{
+				{Name: "grafana", URL: "https://grafana.example.com", DiscoverySource: forecastle.Config,
+					DiscoverySources: []forecastle.DiscoverySource{forecastle.Config, forecastle.ForecastleAppCRD, forecastle.Ingress},
+					Properties:       map[string]string{"Dashboard": "https://grafana/d/app", "Replicas": "3", "Owner": "platform"},
+					PropertyTypes:    map[string]string{"Dashboard": "url"}},
+			},
+		},
+		{
+			name: "DuplicatesByAppID",
+			apps: []forecastle.App{
+				{Name: "vault", URL: "https://vault.internal", AppID: "vault", DiscoverySource: forecastle.Ingress},
+				{Name: "Vault", URL: "https://vault.example.com", AppID: "vault", DiscoverySource: forecastle.Config, NetworkRestricted: true},
+			},
+			want: []forecastle.App{
+				{Name: "Vault", URL: "https://vault.example.com", AppID: "vault", DiscoverySource: forecastle.Config, NetworkRestricted: true,
+					DiscoverySources: []forecastle.DiscoverySource{forecastle.Config, forecastle.Ingress}},
+			},
+		},
+		{
+			name: "AllowedGroupsAreCombined",
+			apps: []forecastle.App{
+				{Name: "argocd", URL: "https://argocd.example.com", DiscoverySource: forecastle.Ingress, AllowedGroups: []string{"platform"}},
+				{Name: "Argo CD", URL: "https://argocd.example.com", DiscoverySource: forecastle.ForecastleAppCRD},
+				{Name: "argo", URL: "https://argocd.example.com", DiscoverySource: forecastle.Config, AllowedGroups: []string{"sre", "platform"}},
+			},
+			want: []forecastle.App{
+				{Name: "argo", URL: "https://argocd.example.com", DiscoverySource: forecastle.Config, AllowedGroups: []string{"sre", "platform"},
+					DiscoverySources: []forecastle.DiscoverySource{forecastle.Config, forecastle.ForecastleAppCRD, forecastle.Ingress}},
+			},
+		},
+		{
+			name: "ConfiguredSourcePrecedence",
+			apps: []forecastle.App{
+				{Name: "from-config", URL: "https://app.example.com", DiscoverySource: forecastle.Config},
+				{Name: "from-ingress", URL: "https://app.example.com", DiscoverySource: forecastle.Ingress},
+			},
+			appConfig: config.Config{Deduplication: config.Deduplication{Enabled: true, SourcePrecedence: []string{"Ingress"}}},
+			want: []forecastle.App{
+				{Name: "from-ingress", URL: "https://app.example.com", DiscoverySource: forecastle.Ingress,
+					DiscoverySources: []forecastle.DiscoverySource{forecastle.Ingress, forecastle.Config}},
+			},
+		},
+		{
+			name: "AppsWithoutURLAreNotMerged",
+			apps: []forecastle.App{
+				{Name: "a", DiscoverySource: forecastle.Ingress},
+				{Name: "a", DiscoverySource: forecastle.Ingress},
+			},
+			want: []forecastle.App{
+				{Name: "a", DiscoverySource: forecastle.Ingress, DiscoverySources: []forecastle.DiscoverySource{forecastle.Ingress}},
+				{Name: "a", DiscoverySource: forecastle.Ingress, DiscoverySources: []forecastle.DiscoverySource{forecastle.Ingress}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Apps(tt.apps, tt.appConfig); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apps() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_normalizeURL(t *testing.T) {
+	tests := []struct {
+		rawURL string
+		want   string
+	}{
+		{rawURL: "", want: ""},
+		{rawURL: "not-a-url", want: ""},
+		{rawURL: "https://Example.com/", want: "https://example.com"},
+		{rawURL: "https://example.com:443/app/", want: "https://example.com/app"},
+		{rawURL: "http://example.com:80/app#section", want: "http://example.com/app"},
+		{rawURL: "http://example.com:8080/app?x=1", want: "http://example.com:8080/app?x=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rawURL, func(t *testing.T) {
+			if got := normalizeURL(tt.rawURL); got != tt.want {
+				t.Errorf("normalizeURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package rewrite
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+)
+
+var (
+	logger = log.New()
+)
+
+// rule is a URL rewrite rule with its host expression compiled
+type rule struct {
+	config.URLRewrite
+	host    *regexp.Regexp
+	sources []forecastle.DiscoverySource
+}
+
+// Apps rewrites the URLs of apps with the URL rewrite rules of the config. Every rule whose scope and host
+// expression match an app is applied in order, so later rules see the URL rewritten by earlier ones
+func Apps(apps []forecastle.App, appConfig config.Config) {
+	rules := compileRules(appConfig.URLRewrites)
+	if len(rules) == 0 {
+		return
+	}
+
+	for i := range apps {
+		if apps[i].URL == "" {
+			continue
+		}
+		parsedURL, err := url.Parse(apps[i].URL)
+		if err != nil || parsedURL.Host == "" {
+			continue
+		}
+
+		rewritten := false
+		for _, rule := range rules {
+			if rule.matches(apps[i], parsedURL) {
+				rule.apply(parsedURL)
+				rewritten = true
+			}
+		}
+		if rewritten {
+			logger.Debugf("Rewrote URL of app '%v' from '%v' to '%v'", apps[i].Name, apps[i].URL, parsedURL)
+			apps[i].URL = parsedURL.String()
+		}
+	}
+}
+
+func compileRules(rewrites []config.URLRewrite) []rule {
+	var rules []rule
+	for i, rewrite := range rewrites {
+		compiled := rule{URLRewrite: rewrite}
+		if rewrite.Host != "" {
+			host, err := regexp.Compile(rewrite.Host)
+			if err != nil {
+				logger.Warnf("Ignoring URL rewrite rule %d with invalid host expression %q: %v", i, rewrite.Host, err)
+				continue
+			}
+			compiled.host = host
+		}
+
+		valid := true
+		for _, name := range rewrite.Sources {
+			source, err := forecastle.ParseDiscoverySource(name)
+			if err != nil {
+				logger.Warnf("Ignoring URL rewrite rule %d: %v", i, err)
+				valid = false
+				break
+			}
+			compiled.sources = append(compiled.sources, source)
+		}
+		if valid {
+			rules = append(rules, compiled)
+		}
+	}
+	return rules
+}
+
+// matches returns true if app is in the scope of the rule and its host matches the host expression
+func (r rule) matches(app forecastle.App, parsedURL *url.URL) bool {
+	if len(r.sources) != 0 && !slices.Contains(r.sources, app.DiscoverySource) {
+		return false
+	}
+	if len(r.Namespaces) != 0 && (app.Origin == nil || !slices.Contains(r.Namespaces, app.Origin.Namespace)) {
+		return false
+	}
+	return r.host == nil || r.host.MatchString(parsedURL.Hostname())
+}
+
+func (r rule) apply(parsedURL *url.URL) {
+	hostname, port := parsedURL.Hostname(), parsedURL.Port()
+
+	if r.host != nil && r.Replacement != "" {
+		hostname = r.host.ReplaceAllString(hostname, r.Replacement)
+	}
+	if r.ForceHTTPS {
+		parsedURL.Scheme = "https"
+	}
+	if r.StripPort {
+		port = ""
+	}
+
+	if port != "" {
+		parsedURL.Host = net.JoinHostPort(hostname, port)
+	} else if strings.Contains(hostname, ":") {
+		parsedURL.Host = "[" + hostname + "]"
+	} else {
+		parsedURL.Host = hostname
+	}
+
+	if r.PathPrefix != "" {
+		parsedURL.Path = strings.TrimSuffix(r.PathPrefix, "/") + "/" + strings.TrimPrefix(parsedURL.Path, "/")
+		parsedURL.RawPath = ""
+	}
+}
@@ -0,0 +1,109 @@
+package rewrite
+
+import (
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+)
+
+func TestApps(t *testing.T) {
+	tests := []struct {
+		name     string
+		app      forecastle.App
+		rewrites []config.URLRewrite
+		want     string
+	}{
+		{
+			name: "NoRules",
+			app:  forecastle.App{URL: "http://grafana.svc.cluster.internal:3000/"},
+			want: "http://grafana.svc.cluster.internal:3000/",
+		},
+		{
+			name: "HostReplacementWithCaptureGroup",
+			app:  forecastle.App{URL: "http://grafana.svc.cluster.internal:3000/d/home?orgId=1"},
+			rewrites: []config.URLRewrite{
+				{Host: `^([a-z0-9-]+)\.svc\.cluster\.internal$`, Replacement: "$1.apps.example.com", ForceHTTPS: true, StripPort: true},
+			},
+			want: "https://grafana.apps.example.com/d/home?orgId=1",
+		},
+		{
+			name: "UnmatchedHost",
+			app:  forecastle.App{URL: "http://grafana.example.com"},
+			rewrites: []config.URLRewrite{
+				{Host: `\.internal$`, ForceHTTPS: true},
+			},
+			want: "http://grafana.example.com",
+		},
+		{
+			name: "RulesApplyInOrder",
+			app:  forecastle.App{URL: "http://ip-10-0-0-1.ec2.internal:8080/app"},
+			rewrites: []config.URLRewrite{
+				{Host: `\.ec2\.internal$`, Replacement: ".proxy.example.com"},
+				{Host: `\.proxy\.example\.com$`, PathPrefix: "/proxy/", StripPort: true},
+			},
+			want: "http://ip-10-0-0-1.proxy.example.com/proxy/app",
+		},
+		{
+			name: "PathPrefixOnRoot",
+			app:  forecastle.App{URL: "https://example.com"},
+			rewrites: []config.URLRewrite{
+				{PathPrefix: "/grafana"},
+			},
+			want: "https://example.com/grafana/",
+		},
+		{
+			name: "IPv6HostKeepsPort",
+			app:  forecastle.App{URL: "http://[fd00::1]:8080/"},
+			rewrites: []config.URLRewrite{
+				{ForceHTTPS: true},
+			},
+			want: "https://[fd00::1]:8080/",
+		},
+		{
+			name: "ScopedByNamespace",
+			app:  forecastle.App{URL: "http://a.internal", Origin: &forecastle.Origin{Namespace: "default"}},
+			rewrites: []config.URLRewrite{
+				{Namespaces: []string{"monitoring"}, ForceHTTPS: true},
+				{Namespaces: []string{"default"}, Host: `\.internal$`, Replacement: ".example.com"},
+			},
+			want: "http://a.example.com",
+		},
+		{
+			name: "ScopedBySource",
+			app:  forecastle.App{URL: "http://a.internal", DiscoverySource: forecastle.Config},
+			rewrites: []config.URLRewrite{
+				{Sources: []string{"Ingress", "HTTPRoute"}, ForceHTTPS: true},
+				{Sources: []string{"Config"}, StripPort: true, PathPrefix: "/config"},
+			},
+			want: "http://a.internal/config/",
+		},
+		{
+			name: "InvalidRulesAreSkipped",
+			app:  forecastle.App{URL: "http://a.internal"},
+			rewrites: []config.URLRewrite{
+				{Host: "(", ForceHTTPS: true},
+				{Sources: []string{"Unknown"}, ForceHTTPS: true},
+				{Host: `\.internal$`, Replacement: ".example.com"},
+			},
+			want: "http://a.example.com",
+		},
+		{
+			name: "URLWithoutHost",
+			app:  forecastle.App{URL: "/relative"},
+			rewrites: []config.URLRewrite{
+				{ForceHTTPS: true},
+			},
+			want: "/relative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps := []forecastle.App{tt.app}
+			Apps(apps, config.Config{URLRewrites: tt.rewrites})
+			if apps[0].URL != tt.want {
+				t.Errorf("Apps() URL = %v, want %v", apps[0].URL, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+var (
+	logger = log.New()
+)
+
+// Config struct for electing a leader among forecastle replicas through a Lease
+type Config struct {
+	LeaseName      string
+	LeaseNamespace string
+	// Identity identifies this replica, usually its pod name
+	Identity string
+}
+
+// Elector tracks whether this replica currently holds the leader Lease
+type Elector struct {
+	leading atomic.Bool
+}
+
+// Run starts campaigning for the leader Lease in the background until ctx is done
+func Run(ctx context.Context, kubeClient kubernetes.Interface, cfg Config) (*Elector, error) {
+	elector := &Elector{}
+
+	leaderElector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      cfg.LeaseName,
+				Namespace: cfg.LeaseNamespace,
+			},
+			Client: kubeClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: cfg.Identity,
+			},
+		},
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		ReleaseOnCancel: true,
+		Name:            cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				logger.Info("Started leading as ", cfg.Identity)
+				elector.leading.Store(true)
+			},
+			OnStoppedLeading: func() {
+				logger.Info("Stopped leading as ", cfg.Identity)
+				elector.leading.Store(false)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != cfg.Identity {
+					logger.Info("Current leader is ", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		// Run returns when leadership is lost, keep campaigning until shutdown
+		for ctx.Err() == nil {
+			leaderElector.Run(ctx)
+		}
+	}()
+
+	return elector, nil
+}
+
+// IsLeader reports whether this replica holds the leader Lease. A nil Elector means leader election
+// is disabled, in which case the replica always leads
+func (e *Elector) IsLeader() bool {
+	return e == nil || e.leading.Load()
+}
@@ -0,0 +1,48 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestElector_IsLeaderWithNilElector(t *testing.T) {
+	var elector *Elector
+	if !elector.IsLeader() {
+		t.Errorf("IsLeader() = false for a nil elector, want true")
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	elector, err := Run(ctx, kubeClient, Config{
+		LeaseName:      "forecastle",
+		LeaseNamespace: "default",
+		Identity:       "forecastle-0",
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !elector.IsLeader() {
+		if time.Now().After(deadline) {
+			t.Fatalf("IsLeader() = false, want the only candidate to become leader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	lease, err := kubeClient.CoordinationV1().Leases("default").Get(ctx, "forecastle", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Getting lease failed: %v", err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "forecastle-0" {
+		t.Errorf("lease holder = %v, want forecastle-0", lease.Spec.HolderIdentity)
+	}
+}
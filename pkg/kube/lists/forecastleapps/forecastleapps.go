@@ -1,25 +1,31 @@
 package forecastleapps
 
 import (
-	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
 	forecastlev1alpha1 "github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned"
 	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var (
+	logger = log.New()
+)
+
 // FilterFunc defined for creating functions that comply with the filtering forecastleapps
-type FilterFunc func(v1alpha1.ForecastleApp, config.Config) bool
+type FilterFunc func(v1beta1.ForecastleApp, config.Config) bool
 
 // List struct is used to list forecastleapps
 type List struct {
 	appConfig        config.Config
 	err              error // Used for forwarding errors
-	items            []v1alpha1.ForecastleApp
+	items            []v1beta1.ForecastleApp
 	forecastleClient forecastlev1alpha1.Interface
 }
 
 // NewList creates an List object that you can use to query forecastleapps
-func NewList(forecastleClient forecastlev1alpha1.Interface, appConfig config.Config, items ...v1alpha1.ForecastleApp) *List {
+func NewList(forecastleClient forecastlev1alpha1.Interface, appConfig config.Config, items ...v1beta1.ForecastleApp) *List {
 	return &List{
 		forecastleClient: forecastleClient,
 		appConfig:        appConfig,
@@ -27,12 +33,23 @@ func NewList(forecastleClient forecastlev1alpha1.Interface, appConfig config.Con
 	}
 }
 
-// Populate function returns a list of forecastleapps
+// Populate function returns a list of forecastleapps, with their annotations translated by the configured
+// annotation scheme. Clusters whose ForecastleApp CRD doesn't serve v1beta1 yet are read through v1alpha1 and
+// converted
 func (il *List) Populate(namespaces ...string) *List {
+	scheme := il.appConfig.GetAnnotationScheme()
 	for _, namespace := range namespaces {
-		forecastleapps, err := il.forecastleClient.ForecastleV1alpha1().ForecastleApps(namespace).List(metav1.ListOptions{})
+		forecastleapps, err := il.forecastleClient.ForecastleV1beta1().ForecastleApps(namespace).List(metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			logger.Debug("ForecastleApp v1beta1 not served, falling back to v1alpha1")
+			forecastleapps, err = il.listV1alpha1(namespace)
+		}
 		if err != nil {
 			il.err = err
+			continue
+		}
+		for i := range forecastleapps.Items {
+			forecastleapps.Items[i].Annotations = scheme.Normalize(forecastleapps.Items[i].Annotations)
 		}
 		il.items = append(il.items, forecastleapps.Items...)
 	}
@@ -40,9 +57,22 @@ func (il *List) Populate(namespaces ...string) *List {
 	return il
 }
 
+func (il *List) listV1alpha1(namespace string) (*v1beta1.ForecastleAppList, error) {
+	forecastleapps, err := il.forecastleClient.ForecastleV1alpha1().ForecastleApps(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	converted := &v1beta1.ForecastleAppList{Items: make([]v1beta1.ForecastleApp, len(forecastleapps.Items))}
+	for i := range forecastleapps.Items {
+		forecastleapps.Items[i].ConvertTo(&converted.Items[i])
+	}
+	return converted, nil
+}
+
 // Filter function applies a filter func that is passed as a parameter to the list of forecastleapps
 func (il *List) Filter(filterFunc FilterFunc) *List {
-	var filtered []v1alpha1.ForecastleApp
+	var filtered []v1beta1.ForecastleApp
 
 	for _, forecastleApp := range il.items {
 		if filterFunc(forecastleApp, il.appConfig) {
@@ -56,6 +86,6 @@ func (il *List) Filter(filterFunc FilterFunc) *List {
 }
 
 // Get function returns the forecastleapps currently present in List
-func (il *List) Get() ([]v1alpha1.ForecastleApp, error) {
+func (il *List) Get() ([]v1beta1.ForecastleApp, error) {
 	return il.items, il.err
 }
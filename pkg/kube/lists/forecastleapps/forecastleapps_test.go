@@ -0,0 +1,111 @@
+package forecastleapps
+
+import (
+	"reflect"
+	"testing"
+
+	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
+	"github.com/stakater/Forecastle/v1/pkg/client/clientset/versioned/fake"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func TestList_Populate(t *testing.T) {
+	forecastleClient := fake.NewSimpleClientset()
+	app := &v1beta1.ForecastleApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec:       v1beta1.ForecastleAppSpec{Name: "App", URL: "https://app.example.com", Tags: []string{"dev"}},
+	}
+	if _, err := forecastleClient.ForecastleV1beta1().ForecastleApps("default").Create(app); err != nil {
+		t.Fatalf("Creating forecastleApp failed: %v", err)
+	}
+
+	got, err := NewList(forecastleClient, config.Config{}).Populate("default").Get()
+	if err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Spec.Tags[0] != "dev" {
+		t.Errorf("Populate() = %v, want the v1beta1 app", got)
+	}
+}
+
+func TestList_PopulateNormalizesAnnotations(t *testing.T) {
+	forecastleClient := fake.NewSimpleClientset()
+	app := &v1beta1.ForecastleApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{
+			"dashboard.example.com/network-restricted": "true",
+			"forecastle.stakater.com/requires-auth":    "true",
+		}},
+		Spec: v1beta1.ForecastleAppSpec{Name: "App", URL: "https://app.example.com"},
+	}
+	if _, err := forecastleClient.ForecastleV1beta1().ForecastleApps("default").Create(app); err != nil {
+		t.Fatalf("Creating forecastleApp failed: %v", err)
+	}
+
+	appConfig := config.Config{Annotations: config.Annotations{Prefix: "dashboard.example.com"}}
+	got, err := NewList(forecastleClient, appConfig).Populate("default").Get()
+	if err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Populate() = %v, want 1 app", got)
+	}
+	want := map[string]string{
+		"dashboard.example.com/network-restricted":   "true",
+		"forecastle.stakater.com/network-restricted": "true",
+	}
+	if !reflect.DeepEqual(got[0].Annotations, want) {
+		t.Errorf("Populate() annotations = %v, want %v", got[0].Annotations, want)
+	}
+}
+
+func TestList_PopulateFallsBackToV1alpha1(t *testing.T) {
+	forecastleClient := fake.NewSimpleClientset()
+	forecastleClient.PrependReactor("list", "forecastleapps", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Version != v1beta1.SchemeGroupVersion.Version {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewNotFound(v1beta1.Resource("forecastleapps"), "")
+	})
+
+	app := &v1alpha1.ForecastleApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: v1alpha1.ForecastleAppSpec{
+			Name:       "App",
+			URL:        "https://app.example.com",
+			Properties: map[string]string{"Version": "1.0"},
+		},
+	}
+	if _, err := forecastleClient.ForecastleV1alpha1().ForecastleApps("default").Create(app); err != nil {
+		t.Fatalf("Creating forecastleApp failed: %v", err)
+	}
+
+	got, err := NewList(forecastleClient, config.Config{}).Populate("default").Get()
+	if err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Populate() returned %d apps, want 1", len(got))
+	}
+	if got[0].Spec.URL != "https://app.example.com" || got[0].Spec.Properties["Version"] != "1.0" {
+		t.Errorf("Populate() = %+v, want the converted v1alpha1 app", got[0].Spec)
+	}
+}
+
+func TestList_Filter(t *testing.T) {
+	items := []v1beta1.ForecastleApp{
+		{Spec: v1beta1.ForecastleAppSpec{Name: "a", Instance: "one"}},
+		{Spec: v1beta1.ForecastleAppSpec{Name: "b", Instance: "two"}},
+	}
+
+	got, _ := NewList(nil, config.Config{}, items...).
+		Filter(func(app v1beta1.ForecastleApp, cfg config.Config) bool { return app.Spec.Instance == "one" }).
+		Get()
+	if len(got) != 1 || got[0].Spec.Name != "a" {
+		t.Errorf("Filter() = %v, want only app a", got)
+	}
+}
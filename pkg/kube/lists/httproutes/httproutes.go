@@ -29,18 +29,23 @@ func NewList(gatewayClient gateway.Interface, appConfig config.Config, items ...
 	}
 }
 
-// Populate returns a list of HTTPRoutes from the specified namespaces
+// Populate returns a list of HTTPRoutes from the specified namespaces, with their annotations translated by the
+// configured annotation scheme
 func (hl *List) Populate(namespaces ...string) *List {
 	if hl.gatewayClient == nil {
 		return hl
 	}
 
+	scheme := hl.appConfig.GetAnnotationScheme()
 	for _, namespace := range namespaces {
 		httpRoutes, err := hl.gatewayClient.GatewayV1().HTTPRoutes(namespace).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
 			hl.err = err
 			continue
 		}
+		for i := range httpRoutes.Items {
+			httpRoutes.Items[i].Annotations = scheme.Normalize(httpRoutes.Items[i].Annotations)
+		}
 		hl.items = append(hl.items, httpRoutes.Items...)
 	}
 
@@ -29,13 +29,17 @@ func NewList(kubeClient kubernetes.Interface, appConfig config.Config, items ...
 	}
 }
 
-// Populate function returns a list of ingresses
+// Populate function returns a list of ingresses, with their annotations translated by the configured annotation scheme
 func (il *List) Populate(namespaces ...string) *List {
+	scheme := il.appConfig.GetAnnotationScheme()
 	for _, namespace := range namespaces {
 		ingresses, err := il.kubeClient.NetworkingV1().Ingresses(namespace).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
 			il.err = err
 		}
+		for i := range ingresses.Items {
+			ingresses.Items[i].Annotations = scheme.Normalize(ingresses.Items[i].Annotations)
+		}
 		il.items = append(il.items, ingresses.Items...)
 	}
 
@@ -0,0 +1,124 @@
+package rbac
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/log"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	logger = log.New()
+)
+
+// Authorizer checks with SubjectAccessReviews whether users have access to namespaces. Results are cached
+// per user, so a user's apps are reviewed once per cache duration
+type Authorizer struct {
+	client kubernetes.Interface
+	cfg    config.RBAC
+	now    func() time.Time
+
+	mu    sync.Mutex
+	users map[string]*userCache
+}
+
+// userCache holds the results of the reviews of a user
+type userCache struct {
+	expiry     time.Time
+	namespaces map[string]bool
+}
+
+// NewAuthorizer creates an Authorizer reviewing the access of cfg with client
+func NewAuthorizer(client kubernetes.Interface, cfg config.RBAC) *Authorizer {
+	return &Authorizer{
+		client: client,
+		cfg:    cfg,
+		now:    time.Now,
+		users:  map[string]*userCache{},
+	}
+}
+
+// Allowed returns true if user, member of groups, has access to namespace. The configured prefixes are added
+// to user and groups. Failed reviews deny access and aren't cached, so they are retried on the next request
+func (a *Authorizer) Allowed(ctx context.Context, user string, groups []string, namespace string) bool {
+	prefixedGroups := make([]string, 0, len(groups))
+	for _, group := range groups {
+		prefixedGroups = append(prefixedGroups, a.cfg.GroupsPrefix+group)
+	}
+	return a.AllowedExact(ctx, a.cfg.UsernamePrefix+user, prefixedGroups, namespace)
+}
+
+// AllowedExact is like Allowed without adding the prefixes, for users that aren't known to the OIDC provider
+func (a *Authorizer) AllowedExact(ctx context.Context, user string, groups []string, namespace string) bool {
+	key := cacheKey(user, groups)
+
+	if allowed, ok := a.cached(key, namespace); ok {
+		return allowed
+	}
+
+	review, err := a.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user,
+			Groups: groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      a.cfg.GetVerb(),
+				Group:     a.cfg.APIGroup,
+				Resource:  a.cfg.GetResource(),
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		logger.Warnf("Failed to review access of user '%v' to namespace '%v': %v", user, namespace, err)
+		return false
+	}
+
+	a.store(key, namespace, review.Status.Allowed)
+	return review.Status.Allowed
+}
+
+func (a *Authorizer) cached(key string, namespace string) (allowed bool, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	cache, found := a.users[key]
+	if !found || a.now().After(cache.expiry) {
+		return false, false
+	}
+	allowed, ok = cache.namespaces[namespace]
+	return allowed, ok
+}
+
+func (a *Authorizer) store(key string, namespace string, allowed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	cache, found := a.users[key]
+	if !found || now.After(cache.expiry) {
+		// Drop the results of users that haven't been seen for a cache duration, so the cache doesn't grow
+		// with every user that ever logged in
+		for otherKey, other := range a.users {
+			if now.After(other.expiry) {
+				delete(a.users, otherKey)
+			}
+		}
+		cache = &userCache{expiry: now.Add(a.cfg.GetCacheDuration()), namespaces: map[string]bool{}}
+		a.users[key] = cache
+	}
+	cache.namespaces[namespace] = allowed
+}
+
+// cacheKey identifies a user along with its groups, since the groups of a user may change between logins
+func cacheKey(user string, groups []string) string {
+	sorted := slices.Clone(groups)
+	slices.Sort(sorted)
+	return user + "\x00" + strings.Join(sorted, "\x00")
+}
@@ -0,0 +1,120 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newReviewingClient returns a fake client allowing access to the namespaces in allowed, recording the reviews
+func newReviewingClient(allowed map[string]bool, reviews *[]authorizationv1.SubjectAccessReviewSpec) *fake.Clientset {
+	client := fake.NewSimpleClientset() //nolint:staticcheck // NewClientset requires generated apply configurations
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		*reviews = append(*reviews, review.Spec)
+		if review.Spec.ResourceAttributes.Namespace == "broken" {
+			return true, nil, errors.New("authorizer unavailable")
+		}
+		review.Status.Allowed = allowed[review.Spec.ResourceAttributes.Namespace]
+		return true, review, nil
+	})
+	return client
+}
+
+func TestAuthorizer_Allowed(t *testing.T) {
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	client := newReviewingClient(map[string]bool{"team-a": true}, &reviews)
+	authorizer := NewAuthorizer(client, config.RBAC{Enabled: true, UsernamePrefix: "oidc:", GroupsPrefix: "oidc:"})
+
+	if !authorizer.Allowed(context.Background(), "jane", []string{"devs"}, "team-a") {
+		t.Error("Allowed() = false, want true for team-a")
+	}
+	if authorizer.Allowed(context.Background(), "jane", []string{"devs"}, "team-b") {
+		t.Error("Allowed() = true, want false for team-b")
+	}
+
+	want := []authorizationv1.SubjectAccessReviewSpec{
+		{User: "oidc:jane", Groups: []string{"oidc:devs"}, ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: "team-a", Verb: "get", Resource: "services"}},
+		{User: "oidc:jane", Groups: []string{"oidc:devs"}, ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: "team-b", Verb: "get", Resource: "services"}},
+	}
+	if !reflect.DeepEqual(reviews, want) {
+		t.Errorf("Reviews = %+v, want %+v", reviews, want)
+	}
+}
+
+func TestAuthorizer_AllowedExact(t *testing.T) {
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	client := newReviewingClient(map[string]bool{"team-a": true}, &reviews)
+	authorizer := NewAuthorizer(client, config.RBAC{Enabled: true, UsernamePrefix: "oidc:", GroupsPrefix: "oidc:"})
+
+	if !authorizer.AllowedExact(context.Background(), "forecastle:api", []string{"automation"}, "team-a") {
+		t.Error("AllowedExact() = false, want true for team-a")
+	}
+	want := []authorizationv1.SubjectAccessReviewSpec{
+		{User: "forecastle:api", Groups: []string{"automation"}, ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: "team-a", Verb: "get", Resource: "services"}},
+	}
+	if !reflect.DeepEqual(reviews, want) {
+		t.Errorf("Reviews = %+v, want %+v", reviews, want)
+	}
+}
+
+func TestAuthorizer_Cache(t *testing.T) {
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	client := newReviewingClient(map[string]bool{"team-a": true}, &reviews)
+	authorizer := NewAuthorizer(client, config.RBAC{Enabled: true, CacheDuration: time.Minute})
+	now := time.Now()
+	authorizer.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	authorizer.Allowed(ctx, "jane", []string{"devs", "ops"}, "team-a")
+	authorizer.Allowed(ctx, "jane", []string{"ops", "devs"}, "team-a")
+	authorizer.Allowed(ctx, "jane", []string{"ops", "devs"}, "team-b")
+	authorizer.Allowed(ctx, "jane", []string{"ops", "devs"}, "team-b")
+	if len(reviews) != 2 {
+		t.Errorf("Expected a review per namespace of the same user, got %d", len(reviews))
+	}
+
+	authorizer.Allowed(ctx, "joe", []string{"ops", "devs"}, "team-a")
+	authorizer.Allowed(ctx, "jane", []string{"devs"}, "team-a")
+	if len(reviews) != 4 {
+		t.Errorf("Expected other users and groups to be reviewed, got %d reviews", len(reviews))
+	}
+
+	now = now.Add(2 * time.Minute)
+	if !authorizer.Allowed(ctx, "jane", []string{"devs", "ops"}, "team-a") || len(reviews) != 5 {
+		t.Errorf("Expected expired results to be reviewed again, got %d reviews", len(reviews))
+	}
+	authorizer.mu.Lock()
+	if len(authorizer.users) != 1 {
+		t.Errorf("Expected the expired results of other users to be dropped, got %d users", len(authorizer.users))
+	}
+	authorizer.mu.Unlock()
+}
+
+func TestAuthorizer_FailedReviewsDenyAndAreRetried(t *testing.T) {
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	authorizer := NewAuthorizer(newReviewingClient(nil, &reviews), config.RBAC{Enabled: true, Verb: "list", APIGroup: "apps", Resource: "deployments"})
+
+	for range 2 {
+		if authorizer.Allowed(context.Background(), "jane", nil, "broken") {
+			t.Error("Allowed() = true, want false when the review fails")
+		}
+	}
+	if len(reviews) != 2 {
+		t.Errorf("Expected failed reviews to be retried, got %d reviews", len(reviews))
+	}
+	if got := reviews[0].ResourceAttributes; got.Verb != "list" || got.Group != "apps" || got.Resource != "deployments" {
+		t.Errorf("Expected the configured access to be reviewed, got %+v", got)
+	}
+}
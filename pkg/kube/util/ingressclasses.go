@@ -0,0 +1,25 @@
+package util
+
+import (
+	"context"
+
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetDefaultIngressClassName returns the name of the IngressClass marked as cluster default,
+// or an empty string if there is none
+func GetDefaultIngressClassName(kubeClient kubernetes.Interface) (string, error) {
+	ingressClasses, err := kubeClient.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, ingressClass := range ingressClasses.Items {
+		if ingressClass.Annotations[v1.AnnotationIsDefaultIngressClass] == "true" {
+			return ingressClass.Name, nil
+		}
+	}
+	return "", nil
+}
@@ -2,9 +2,11 @@ package util
 
 import (
 	"context"
+	"slices"
 
 	"github.com/stakater/Forecastle/v1/pkg/config"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
@@ -39,6 +41,34 @@ func PopulateNamespaceList(kubeClient kubernetes.Interface, namespaceSelector co
 	return removeDuplicates(append(namespaces, namespaceSelector.MatchNames...)), nil
 }
 
+// GetNamespaces returns the namespace objects for the given namespace names keyed by name. Passing
+// metav1.NamespaceAll fetches every namespace in the cluster
+func GetNamespaces(kubeClient kubernetes.Interface, namespaceNames []string) (map[string]*corev1.Namespace, error) {
+	namespaces := map[string]*corev1.Namespace{}
+
+	if slices.Contains(namespaceNames, metav1.NamespaceAll) {
+		nsList, err := kubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range nsList.Items {
+			namespaces[nsList.Items[i].Name] = &nsList.Items[i]
+		}
+		return namespaces, nil
+	}
+
+	var err error
+	for _, namespaceName := range namespaceNames {
+		namespace, getErr := kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespaceName, metav1.GetOptions{})
+		if getErr != nil {
+			err = getErr
+			continue
+		}
+		namespaces[namespaceName] = namespace
+	}
+	return namespaces, err
+}
+
 func removeDuplicates(elements []string) []string {
 	// Use map to record duplicates as we find them.
 	encountered := map[string]bool{}
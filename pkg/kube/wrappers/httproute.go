@@ -1,15 +1,29 @@
 package wrappers
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/derivation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
+// traefikGroups are the API groups of Traefik Middlewares, current and legacy
+var traefikGroups = []string{"traefik.io", "traefik.containo.us"}
+
 // HTTPRouteWrapper wraps a Gateway API HTTPRoute
 type HTTPRouteWrapper struct {
 	httpRoute *gatewayv1.HTTPRoute
+	namespace *NamespaceWrapper
+	// derivation derives the name and group when they aren't set by annotations
+	derivation *derivation.Rules
+	// recommendedLabels uses the app.kubernetes.io labels as defaults when annotations aren't set
+	recommendedLabels bool
 }
 
 // NewHTTPRouteWrapper creates a new HTTPRouteWrapper
@@ -17,9 +31,54 @@ func NewHTTPRouteWrapper(httpRoute *gatewayv1.HTTPRoute) *HTTPRouteWrapper {
 	return &HTTPRouteWrapper{httpRoute: httpRoute}
 }
 
-// GetAnnotationValue extracts an annotation value from the HTTPRoute
+// WithNamespace sets the namespace of the HTTPRoute whose forecastle annotations and labels are used as defaults
+func (hw *HTTPRouteWrapper) WithNamespace(namespace *corev1.Namespace) *HTTPRouteWrapper {
+	hw.namespace = NewNamespaceWrapper(namespace)
+	return hw
+}
+
+// WithDerivationRules sets the rules deriving the name and group of the HTTPRoute when they aren't set by annotations
+func (hw *HTTPRouteWrapper) WithDerivationRules(rules *derivation.Rules) *HTTPRouteWrapper {
+	hw.derivation = rules
+	return hw
+}
+
+// WithRecommendedLabels enables using the app.kubernetes.io labels of the HTTPRoute as defaults when annotations aren't set
+func (hw *HTTPRouteWrapper) WithRecommendedLabels(enabled bool) *HTTPRouteWrapper {
+	hw.recommendedLabels = enabled
+	return hw
+}
+
+// GetRecommendedLabel returns the value of a Kubernetes recommended label of the HTTPRoute, or an empty string if
+// recommended labels are disabled
+func (hw *HTTPRouteWrapper) GetRecommendedLabel(labelKey string) string {
+	if !hw.recommendedLabels {
+		return ""
+	}
+	return hw.httpRoute.Labels[labelKey]
+}
+
+// metadata returns the metadata derivation rules are evaluated against
+func (hw *HTTPRouteWrapper) metadata() derivation.Metadata {
+	namespaceLabels, namespaceAnnotations := hw.namespace.GetMetadata()
+	return derivation.Metadata{
+		Kind:                 "HTTPRoute",
+		Name:                 hw.httpRoute.Name,
+		Namespace:            hw.httpRoute.Namespace,
+		Labels:               hw.httpRoute.Labels,
+		Annotations:          hw.httpRoute.Annotations,
+		NamespaceLabels:      namespaceLabels,
+		NamespaceAnnotations: namespaceAnnotations,
+		Host:                 hw.getHost(),
+	}
+}
+
+// GetAnnotationValue extracts an annotation value from the HTTPRoute, falling back to the defaults of its namespace
 func (hw *HTTPRouteWrapper) GetAnnotationValue(annotationKey string) string {
-	return getAnnotationValue(hw.httpRoute.Annotations, annotationKey)
+	if value := getAnnotationValue(hw.httpRoute.Annotations, annotationKey); value != "" {
+		return value
+	}
+	return hw.namespace.GetAnnotationValue(annotationKey)
 }
 
 // GetName returns the name of the HTTPRoute (from annotation or resource name)
@@ -27,6 +86,12 @@ func (hw *HTTPRouteWrapper) GetName() string {
 	if nameFromAnnotation := hw.GetAnnotationValue(annotations.ForecastleAppNameAnnotation); nameFromAnnotation != "" {
 		return nameFromAnnotation
 	}
+	if nameFromLabel := hw.GetRecommendedLabel(annotations.AppNameLabel); nameFromLabel != "" {
+		return nameFromLabel
+	}
+	if derivedName := hw.derivation.Name(hw.metadata()); derivedName != "" {
+		return derivedName
+	}
 	return hw.httpRoute.Name
 }
 
@@ -35,34 +100,151 @@ func (hw *HTTPRouteWrapper) GetNamespace() string {
 	return hw.httpRoute.Namespace
 }
 
-// GetGroup returns the group name (normalized to lowercase)
+// GetGroup returns the group slug, the group name normalized to lowercase
 func (hw *HTTPRouteWrapper) GetGroup() string {
-	if groupFromAnnotation := hw.GetAnnotationValue(annotations.ForecastleGroupAnnotation); groupFromAnnotation != "" {
-		return strings.ToLower(groupFromAnnotation)
+	return strings.ToLower(hw.GetGroupDisplayName())
+}
+
+// IsGroupFromNamespace returns true if the group falls back to the namespace, rather than being set by an
+// annotation, recommended label or derivation rule
+func (hw *HTTPRouteWrapper) IsGroupFromNamespace() bool {
+	return hw.GetAnnotationValue(annotations.ForecastleGroupAnnotation) == "" &&
+		hw.GetRecommendedLabel(annotations.AppPartOfLabel) == "" &&
+		hw.derivation.Group(hw.metadata()) == ""
+}
+
+// GetGroupDisplayName returns the group name in its original casing
+func (hw *HTTPRouteWrapper) GetGroupDisplayName() string {
+	if groupFromAnnotation := getAnnotationValue(hw.httpRoute.Annotations, annotations.ForecastleGroupAnnotation); groupFromAnnotation != "" {
+		return groupFromAnnotation
+	}
+	if groupFromLabel := hw.GetRecommendedLabel(annotations.AppPartOfLabel); groupFromLabel != "" {
+		return groupFromLabel
+	}
+	if derivedGroup := hw.derivation.Group(hw.metadata()); derivedGroup != "" {
+		return derivedGroup
+	}
+	if defaultGroup := hw.namespace.GetAnnotationValue(annotations.ForecastleGroupAnnotation); defaultGroup != "" {
+		return defaultGroup
+	}
+	if displayName := hw.namespace.GetDisplayName(); displayName != "" {
+		return displayName
 	}
-	return strings.ToLower(hw.GetNamespace())
+	return hw.GetNamespace()
 }
 
-// GetProperties parses custom properties from annotation
+// GetTags func parses the comma separated tags of the HTTPRoute
+func (hw *HTTPRouteWrapper) GetTags() []string {
+	return parseTags(hw.GetAnnotationValue(annotations.ForecastleTagsAnnotation))
+}
+
+// GetAllowedGroups func parses the comma separated user groups allowed to see the app of the HTTPRoute
+func (hw *HTTPRouteWrapper) GetAllowedGroups() []string {
+	return parseTags(hw.GetAnnotationValue(annotations.ForecastleAllowedGroupsAnnotation))
+}
+
+// GetWeight func parses the weight of the HTTPRoute, defaulting to 0
+func (hw *HTTPRouteWrapper) GetWeight() int {
+	weight, err := parseWeight(hw.GetAnnotationValue(annotations.ForecastleWeightAnnotation))
+	if err != nil {
+		logger.Warn(err)
+	}
+	return weight
+}
+
+// GetLinks func parses the secondary links of the HTTPRoute. Links without a label or a valid URL are skipped
+func (hw *HTTPRouteWrapper) GetLinks() []forecastle.Link {
+	return getLinks(hw.httpRoute.Annotations)
+}
+
+// GetProperties parses custom properties from annotation, merged on top of the defaults of its namespace
 func (hw *HTTPRouteWrapper) GetProperties() map[string]string {
-	if propertiesFromAnnotation := hw.GetAnnotationValue(annotations.ForecastlePropertiesAnnotation); propertiesFromAnnotation != "" {
-		return makeMap(propertiesFromAnnotation)
+	return mergeProperties(hw.namespace.GetProperties(), getProperties(hw.httpRoute.Annotations))
+}
+
+// GetParentGateways returns the Gateways the HTTPRoute attaches to. Parent refs to other kinds are skipped
+// and a missing namespace defaults to the namespace of the HTTPRoute
+func (hw *HTTPRouteWrapper) GetParentGateways() []types.NamespacedName {
+	var gateways []types.NamespacedName
+	for _, parentRef := range hw.httpRoute.Spec.ParentRefs {
+		if parentRef.Group != nil && string(*parentRef.Group) != gatewayv1.GroupName {
+			continue
+		}
+		if parentRef.Kind != nil && string(*parentRef.Kind) != "Gateway" {
+			continue
+		}
+
+		namespace := hw.GetNamespace()
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		gateways = append(gateways, types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)})
 	}
-	return nil
+	return gateways
 }
 
-// GetURL extracts the URL from the HTTPRoute
+// GetMiddlewares returns the Traefik middlewares the rules and backends of the HTTPRoute reference with
+// ExtensionRef filters. Middlewares are in the namespace of the HTTPRoute
+func (hw *HTTPRouteWrapper) GetMiddlewares() []types.NamespacedName {
+	var middlewares []types.NamespacedName
+	addMiddlewares := func(filters []gatewayv1.HTTPRouteFilter) {
+		for _, filter := range filters {
+			ref := filter.ExtensionRef
+			if filter.Type != gatewayv1.HTTPRouteFilterExtensionRef || ref == nil || ref.Kind != "Middleware" ||
+				!slices.Contains(traefikGroups, string(ref.Group)) {
+				continue
+			}
+			middleware := types.NamespacedName{Namespace: hw.GetNamespace(), Name: string(ref.Name)}
+			if !slices.Contains(middlewares, middleware) {
+				middlewares = append(middlewares, middleware)
+			}
+		}
+	}
+	for _, rule := range hw.httpRoute.Spec.Rules {
+		addMiddlewares(rule.Filters)
+		for _, backendRef := range rule.BackendRefs {
+			addMiddlewares(backendRef.Filters)
+		}
+	}
+	return middlewares
+}
+
+// getHost returns the first hostname of the HTTPRoute that isn't a wildcard, ignoring the URL annotation
+func (hw *HTTPRouteWrapper) getHost() string {
+	for _, hostname := range hw.httpRoute.Spec.Hostnames {
+		if !isWildcardHost(string(hostname)) {
+			return string(hostname)
+		}
+	}
+	return ""
+}
+
+// GetURL extracts the URL from the HTTPRoute. Returns an empty string if the HTTPRoute has no usable URL
 func (hw *HTTPRouteWrapper) GetURL() string {
+	url, err := hw.ResolveURL()
+	if err != nil {
+		logger.Warn(err)
+		return ""
+	}
+	return url
+}
+
+// ResolveURL returns the URL of the HTTPRoute from its URL annotation, or else from its first hostname that
+// isn't a wildcard. Invalid URL annotations are logged and ignored. Returns an error if the HTTPRoute has no
+// usable URL
+func (hw *HTTPRouteWrapper) ResolveURL() (string, error) {
 	if urlFromAnnotation := getAndValidateURLAnnotation(hw.httpRoute.Annotations, annotations.ForecastleURLAnnotation); urlFromAnnotation != "" {
-		return urlFromAnnotation
+		return urlFromAnnotation, nil
 	}
 
 	if len(hw.httpRoute.Spec.Hostnames) == 0 {
-		logger.Warn("No hostnames defined for HTTPRoute: ", hw.httpRoute.Name)
-		return ""
+		return "", fmt.Errorf("no hostnames defined for HTTPRoute %q", hw.httpRoute.Name)
 	}
 
-	host := string(hw.httpRoute.Spec.Hostnames[0])
+	host := hw.getHost()
+	if host == "" {
+		return "", fmt.Errorf("HTTPRoute %q only has wildcard hostnames, set the %v annotation", hw.httpRoute.Name, annotations.ForecastleURLAnnotation)
+	}
 	// TLS is configured on Gateway listener, not HTTPRoute - default to https
-	return "https://" + host
+	return ParseURL("https://" + host)
 }
@@ -1,11 +1,13 @@
 package wrappers
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	"github.com/stakater/Forecastle/v1/pkg/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -319,3 +321,78 @@ func TestGetAndValidateURLAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPRouteWrapper_GetParentGateways(t *testing.T) {
+	otherNamespace := gatewayv1.Namespace("infra")
+	serviceKind := gatewayv1.Kind("Service")
+	coreGroup := gatewayv1.Group("")
+
+	tests := []struct {
+		name       string
+		parentRefs []gatewayv1.ParentReference
+		want       []types.NamespacedName
+	}{
+		{
+			name: "NoParentRefs",
+			want: nil,
+		},
+		{
+			name:       "GatewayInSameNamespace",
+			parentRefs: []gatewayv1.ParentReference{{Name: "internal-gw"}},
+			want:       []types.NamespacedName{{Namespace: "default", Name: "internal-gw"}},
+		},
+		{
+			name:       "GatewayInOtherNamespace",
+			parentRefs: []gatewayv1.ParentReference{{Name: "public-gw", Namespace: &otherNamespace}},
+			want:       []types.NamespacedName{{Namespace: "infra", Name: "public-gw"}},
+		},
+		{
+			name: "NonGatewayParentsSkipped",
+			parentRefs: []gatewayv1.ParentReference{
+				{Name: "mesh-svc", Kind: &serviceKind, Group: &coreGroup},
+				{Name: "internal-gw"},
+			},
+			want: []types.NamespacedName{{Namespace: "default", Name: "internal-gw"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpRoute := testutil.CreateHTTPRouteWithNamespace("test-route", "default")
+			httpRoute.Spec.ParentRefs = tt.parentRefs
+			if got := NewHTTPRouteWrapper(httpRoute).GetParentGateways(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HTTPRouteWrapper.GetParentGateways() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPRouteWrapper_GetMiddlewares(t *testing.T) {
+	middlewareFilter := func(group string, kind string, name string) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1.LocalObjectReference{Group: gatewayv1.Group(group), Kind: gatewayv1.Kind(kind), Name: gatewayv1.ObjectName(name)},
+		}
+	}
+
+	httpRoute := testutil.CreateHTTPRouteWithNamespace("test-route", "apps")
+	httpRoute.Spec.Rules = []gatewayv1.HTTPRouteRule{
+		{
+			Filters: []gatewayv1.HTTPRouteFilter{
+				middlewareFilter("traefik.io", "Middleware", "office-only"),
+				middlewareFilter("example.com", "Middleware", "other-extension"),
+				{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier},
+			},
+			BackendRefs: []gatewayv1.HTTPBackendRef{{Filters: []gatewayv1.HTTPRouteFilter{
+				middlewareFilter("traefik.containo.us", "Middleware", "sso"),
+			}}},
+		},
+		{
+			Filters: []gatewayv1.HTTPRouteFilter{middlewareFilter("traefik.io", "Middleware", "office-only")},
+		},
+	}
+
+	want := []types.NamespacedName{{Namespace: "apps", Name: "office-only"}, {Namespace: "apps", Name: "sso"}}
+	if got := NewHTTPRouteWrapper(httpRoute).GetMiddlewares(); !reflect.DeepEqual(got, want) {
+		t.Errorf("HTTPRouteWrapper.GetMiddlewares() = %v, want %v", got, want)
+	}
+}
@@ -1,11 +1,15 @@
 package wrappers
 
 import (
-	"net/url"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/derivation"
 	"github.com/stakater/Forecastle/v1/pkg/log"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 )
 
@@ -15,7 +19,12 @@ var (
 
 // IngressWrapper struct wraps a kubernetes ingress object
 type IngressWrapper struct {
-	ingress *v1.Ingress
+	ingress   *v1.Ingress
+	namespace *NamespaceWrapper
+	// derivation derives the name and group when they aren't set by annotations
+	derivation *derivation.Rules
+	// recommendedLabels uses the app.kubernetes.io labels as defaults when annotations aren't set
+	recommendedLabels bool
 }
 
 // NewIngressWrapper func creates an instance of IngressWrapper
@@ -25,9 +34,55 @@ func NewIngressWrapper(ingress *v1.Ingress) *IngressWrapper {
 	}
 }
 
-// GetAnnotationValue extracts an annotation's value present on the ingress wrapped by the object
+// WithNamespace func sets the namespace of the ingress whose forecastle annotations and labels are used as defaults
+func (iw *IngressWrapper) WithNamespace(namespace *corev1.Namespace) *IngressWrapper {
+	iw.namespace = NewNamespaceWrapper(namespace)
+	return iw
+}
+
+// WithDerivationRules sets the rules deriving the name and group of the Ingress when they aren't set by annotations
+func (iw *IngressWrapper) WithDerivationRules(rules *derivation.Rules) *IngressWrapper {
+	iw.derivation = rules
+	return iw
+}
+
+// WithRecommendedLabels enables using the app.kubernetes.io labels of the Ingress as defaults when annotations aren't set
+func (iw *IngressWrapper) WithRecommendedLabels(enabled bool) *IngressWrapper {
+	iw.recommendedLabels = enabled
+	return iw
+}
+
+// GetRecommendedLabel returns the value of a Kubernetes recommended label of the Ingress, or an empty string if
+// recommended labels are disabled
+func (iw *IngressWrapper) GetRecommendedLabel(labelKey string) string {
+	if !iw.recommendedLabels {
+		return ""
+	}
+	return iw.ingress.Labels[labelKey]
+}
+
+// metadata returns the metadata derivation rules are evaluated against
+func (iw *IngressWrapper) metadata() derivation.Metadata {
+	namespaceLabels, namespaceAnnotations := iw.namespace.GetMetadata()
+	return derivation.Metadata{
+		Kind:                 "Ingress",
+		Name:                 iw.ingress.Name,
+		Namespace:            iw.ingress.Namespace,
+		Labels:               iw.ingress.Labels,
+		Annotations:          iw.ingress.Annotations,
+		NamespaceLabels:      namespaceLabels,
+		NamespaceAnnotations: namespaceAnnotations,
+		Host:                 iw.getHost(),
+	}
+}
+
+// GetAnnotationValue extracts an annotation's value present on the ingress wrapped by the object,
+// falling back to the defaults of its namespace
 func (iw *IngressWrapper) GetAnnotationValue(annotationKey string) string {
-	return getAnnotationValue(iw.ingress.Annotations, annotationKey)
+	if value := getAnnotationValue(iw.ingress.Annotations, annotationKey); value != "" {
+		return value
+	}
+	return iw.namespace.GetAnnotationValue(annotationKey)
 }
 
 // GetName func extracts name of the ingress wrapped by the object
@@ -35,6 +90,12 @@ func (iw *IngressWrapper) GetName() string {
 	if nameFromAnnotation := iw.GetAnnotationValue(annotations.ForecastleAppNameAnnotation); nameFromAnnotation != "" {
 		return nameFromAnnotation
 	}
+	if nameFromLabel := iw.GetRecommendedLabel(annotations.AppNameLabel); nameFromLabel != "" {
+		return nameFromLabel
+	}
+	if derivedName := iw.derivation.Name(iw.metadata()); derivedName != "" {
+		return derivedName
+	}
 	return iw.ingress.Name
 }
 
@@ -43,35 +104,95 @@ func (iw *IngressWrapper) GetNamespace() string {
 	return iw.ingress.Namespace
 }
 
-// GetGroup func extracts group name from the ingress (normalized to lowercase for consistent grouping)
+// GetGroup func extracts the group slug from the ingress (normalized to lowercase for consistent grouping)
 func (iw *IngressWrapper) GetGroup() string {
-	if groupFromAnnotation := iw.GetAnnotationValue(annotations.ForecastleGroupAnnotation); groupFromAnnotation != "" {
-		return strings.ToLower(groupFromAnnotation)
+	return strings.ToLower(iw.GetGroupDisplayName())
+}
+
+// IsGroupFromNamespace returns true if the group falls back to the namespace, rather than being set by an
+// annotation, recommended label or derivation rule
+func (iw *IngressWrapper) IsGroupFromNamespace() bool {
+	return iw.GetAnnotationValue(annotations.ForecastleGroupAnnotation) == "" &&
+		iw.GetRecommendedLabel(annotations.AppPartOfLabel) == "" &&
+		iw.derivation.Group(iw.metadata()) == ""
+}
+
+// GetGroupDisplayName func extracts the group name from the ingress in its original casing
+func (iw *IngressWrapper) GetGroupDisplayName() string {
+	if groupFromAnnotation := getAnnotationValue(iw.ingress.Annotations, annotations.ForecastleGroupAnnotation); groupFromAnnotation != "" {
+		return groupFromAnnotation
+	}
+	if groupFromLabel := iw.GetRecommendedLabel(annotations.AppPartOfLabel); groupFromLabel != "" {
+		return groupFromLabel
+	}
+	if derivedGroup := iw.derivation.Group(iw.metadata()); derivedGroup != "" {
+		return derivedGroup
+	}
+	// The default group of the namespace only applies to apps whose group isn't set or derived otherwise
+	if defaultGroup := iw.namespace.GetAnnotationValue(annotations.ForecastleGroupAnnotation); defaultGroup != "" {
+		return defaultGroup
 	}
-	return strings.ToLower(iw.GetNamespace())
+	if displayName := iw.namespace.GetDisplayName(); displayName != "" {
+		return displayName
+	}
+	return iw.GetNamespace()
 }
 
-func (iw *IngressWrapper) GetProperties() map[string]string {
-	if propertiesFromAnnotations := iw.GetAnnotationValue(annotations.ForecastlePropertiesAnnotation); propertiesFromAnnotations != "" {
-		return makeMap(propertiesFromAnnotations)
+// GetIngressClassName func extracts the ingress class of the ingress wrapped by the object from spec.ingressClassName
+// or the legacy kubernetes.io/ingress.class annotation. Returns empty string if neither is set
+func (iw *IngressWrapper) GetIngressClassName() string {
+	if iw.ingress.Spec.IngressClassName != nil && *iw.ingress.Spec.IngressClassName != "" {
+		return *iw.ingress.Spec.IngressClassName
 	}
-	return nil
+	return iw.GetAnnotationValue(annotations.IngressClassAnnotation)
+}
+
+// GetTags func parses the comma separated tags of the ingress
+func (iw *IngressWrapper) GetTags() []string {
+	return parseTags(iw.GetAnnotationValue(annotations.ForecastleTagsAnnotation))
 }
 
-// GetURL func extracts url of the ingress wrapped by the object
+// GetAllowedGroups func parses the comma separated user groups allowed to see the app of the ingress
+func (iw *IngressWrapper) GetAllowedGroups() []string {
+	return parseTags(iw.GetAnnotationValue(annotations.ForecastleAllowedGroupsAnnotation))
+}
+
+// GetWeight func parses the weight of the ingress, defaulting to 0
+func (iw *IngressWrapper) GetWeight() int {
+	weight, err := parseWeight(iw.GetAnnotationValue(annotations.ForecastleWeightAnnotation))
+	if err != nil {
+		logger.Warn(err)
+	}
+	return weight
+}
+
+// GetLinks func parses the secondary links of the ingress. Links without a label or a valid URL are skipped
+func (iw *IngressWrapper) GetLinks() []forecastle.Link {
+	return getLinks(iw.ingress.Annotations)
+}
+
+// GetProperties func parses the properties of the ingress, merged on top of the defaults of its namespace
+func (iw *IngressWrapper) GetProperties() map[string]string {
+	return mergeProperties(iw.namespace.GetProperties(), getProperties(iw.ingress.Annotations))
+}
+
+// GetURL func extracts url of the ingress wrapped by the object. Returns an empty string if the ingress has
+// no usable URL
 func (iw *IngressWrapper) GetURL() string {
+	url, err := iw.ResolveURL()
+	if err != nil {
+		logger.Warn(err)
+		return ""
+	}
+	return url
+}
 
+// ResolveURL func returns the URL of the ingress from its URL annotation, or else from its TLS hosts, rule hosts or
+// load balancer status, with the literal prefix of the path of its rule. Wildcard hosts are skipped. Returns an
+// error if the ingress has no usable URL
+func (iw *IngressWrapper) ResolveURL() (string, error) {
 	if urlFromAnnotation := iw.GetAnnotationValue(annotations.ForecastleURLAnnotation); urlFromAnnotation != "" {
-		parsedURL, err := url.Parse(urlFromAnnotation)
-		if err != nil {
-			logger.Warn(err)
-			return ""
-		}
-		if parsedURL.Scheme == "" {
-			logger.Warnf("URL %q is missing a scheme", urlFromAnnotation)
-			return ""
-		}
-		return parsedURL.String()
+		return ParseURL(urlFromAnnotation)
 	}
 
 	var url string
@@ -82,15 +203,27 @@ func (iw *IngressWrapper) GetURL() string {
 		url = "http://" + host
 	} else if host, exists := iw.tryGetStatusHost(); exists { // Fallback to status host if defined
 		url = "http://" + host
+	} else if iw.hasWildcardHost() {
+		return "", fmt.Errorf("ingress %q only has wildcard hosts, set the %v annotation", iw.ingress.GetName(), annotations.ForecastleURLAnnotation)
 	} else {
-		logger.Warn("Unable to infer host for ingress: ", iw.ingress.GetName())
-		return ""
+		return "", fmt.Errorf("unable to infer host for ingress %q", iw.ingress.GetName())
 	}
 
 	// Append path if defined
 	url += iw.getIngressSubPath()
 
-	return url
+	return ParseURL(url)
+}
+
+// getHost returns the host the URL of the ingress is inferred from, ignoring the URL annotation
+func (iw *IngressWrapper) getHost() string {
+	if host, exists := iw.tryGetTLSHost(); exists {
+		return host
+	} else if host, exists := iw.tryGetRuleHost(); exists {
+		return host
+	}
+	host, _ := iw.tryGetStatusHost()
+	return host
 }
 
 func (iw *IngressWrapper) supportsTLS() bool {
@@ -98,8 +231,12 @@ func (iw *IngressWrapper) supportsTLS() bool {
 }
 
 func (iw *IngressWrapper) tryGetTLSHost() (string, bool) {
-	if iw.supportsTLS() && len(iw.ingress.Spec.TLS[0].Hosts) > 0 {
-		return iw.ingress.Spec.TLS[0].Hosts[0], true
+	for _, tls := range iw.ingress.Spec.TLS {
+		for _, host := range tls.Hosts {
+			if host != "" && !isWildcardHost(host) {
+				return host, true
+			}
+		}
 	}
 	return "", false
 }
@@ -109,12 +246,26 @@ func (iw *IngressWrapper) rulesExist() bool {
 }
 
 func (iw *IngressWrapper) tryGetRuleHost() (string, bool) {
-	if iw.rulesExist() && iw.ingress.Spec.Rules[0].Host != "" {
-		return iw.ingress.Spec.Rules[0].Host, true
+	for _, rule := range iw.ingress.Spec.Rules {
+		if rule.Host != "" && !isWildcardHost(rule.Host) {
+			return rule.Host, true
+		}
 	}
 	return "", false
 }
 
+// hasWildcardHost returns true if any TLS or rule host of the ingress is a wildcard host
+func (iw *IngressWrapper) hasWildcardHost() bool {
+	for _, tls := range iw.ingress.Spec.TLS {
+		if slices.ContainsFunc(tls.Hosts, isWildcardHost) {
+			return true
+		}
+	}
+	return slices.ContainsFunc(iw.ingress.Spec.Rules, func(rule v1.IngressRule) bool {
+		return isWildcardHost(rule.Host)
+	})
+}
+
 func (iw *IngressWrapper) statusLoadBalancerExist() bool {
 	return len(iw.ingress.Status.LoadBalancer.Ingress) > 0
 }
@@ -125,20 +276,36 @@ func (iw *IngressWrapper) tryGetStatusHost() (string, bool) {
 		if ingressStatus.Hostname != "" {
 			return ingressStatus.Hostname, true
 		} else if ingressStatus.IP != "" {
-			return ingressStatus.IP, true
+			return formatHost(ingressStatus.IP), true
 		}
 	}
 	return "", false
 }
 
+// getIngressSubPath returns the literal prefix of the first path of the rule of the host the URL is inferred from,
+// or else of the first rule
 func (iw *IngressWrapper) getIngressSubPath() string {
 	if iw.rulesExist() {
 		rule := iw.ingress.Spec.Rules[0]
+		host := iw.getHost()
+		for _, r := range iw.ingress.Spec.Rules {
+			if r.Host == host {
+				rule = r
+				break
+			}
+		}
 		if rule.HTTP != nil {
 			if len(rule.HTTP.Paths) > 0 {
-				return rule.HTTP.Paths[0].Path
+				path := rule.HTTP.Paths[0]
+				return normalizeIngressPath(path.Path, path.PathType, iw.usesRegexPaths())
 			}
 		}
 	}
 	return ""
 }
+
+// usesRegexPaths returns true if the ingress controller interprets the paths of the ingress as regular expressions
+func (iw *IngressWrapper) usesRegexPaths() bool {
+	return getAnnotationValue(iw.ingress.Annotations, annotations.NginxUseRegexAnnotation) == "true" ||
+		getAnnotationValue(iw.ingress.Annotations, annotations.NginxRewriteTargetAnnotation) != ""
+}
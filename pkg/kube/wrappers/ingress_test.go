@@ -5,8 +5,12 @@ import (
 	"testing"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	"github.com/stakater/Forecastle/v1/pkg/config"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle/derivation"
 	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewIngressWrapper(t *testing.T) {
@@ -112,6 +116,128 @@ func TestIngressWrapper_GetName(t *testing.T) {
 	}
 }
 
+func TestIngressWrapper_DerivationRules(t *testing.T) {
+	rules := derivation.NewRules(config.Derivation{
+		Name:  []string{`{{ regexReplaceAll "\\..*$" .Host "" }}`},
+		Group: []string{`{{ index .Labels "app.kubernetes.io/part-of" }}`},
+	})
+
+	ingress := testutil.CreateIngressWithHost("grafana-ingress", "grafana-dashboard.example.com")
+	ingress.Namespace = "monitoring"
+	ingress.Labels = map[string]string{"app.kubernetes.io/part-of": "Observability"}
+
+	iw := NewIngressWrapper(ingress).WithDerivationRules(rules)
+	if got := iw.GetName(); got != "grafana-dashboard" {
+		t.Errorf("IngressWrapper.GetName() = %v, want grafana-dashboard", got)
+	}
+	if got := iw.GetGroupDisplayName(); got != "Observability" || iw.IsGroupFromNamespace() {
+		t.Errorf("IngressWrapper.GetGroupDisplayName() = %v, want Observability not from namespace", got)
+	}
+
+	// Annotations win over derivation rules, and unmatched rules fall back to the namespace
+	annotated := testutil.AddAnnotationToIngress(testutil.CreateIngressWithNamespace("app", "tools"), annotations.ForecastleAppNameAnnotation, "App")
+	iw = NewIngressWrapper(annotated).WithDerivationRules(rules)
+	if got := iw.GetName(); got != "App" {
+		t.Errorf("IngressWrapper.GetName() = %v, want App", got)
+	}
+	if got := iw.GetGroup(); got != "tools" || !iw.IsGroupFromNamespace() {
+		t.Errorf("IngressWrapper.GetGroup() = %v, want tools from namespace", got)
+	}
+}
+
+func TestIngressWrapper_NamespaceDefaultGroup(t *testing.T) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "monitoring",
+		Annotations: map[string]string{annotations.ForecastleGroupAnnotation: "Team Default"},
+	}}
+	rules := derivation.NewRules(config.Derivation{Group: []string{`{{ index .Labels "tier" }}`}})
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+		want        string
+	}{
+		{name: "Annotation", annotations: map[string]string{annotations.ForecastleGroupAnnotation: "Ops"}, want: "Ops"},
+		{name: "PartOfLabel", labels: map[string]string{annotations.AppPartOfLabel: "Observability"}, want: "Observability"},
+		{name: "DerivationRule", labels: map[string]string{"tier": "Backend"}, want: "Backend"},
+		{name: "NamespaceDefault", want: "Team Default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingress := testutil.CreateIngressWithNamespace("grafana-ingress", "monitoring")
+			ingress.Annotations = tt.annotations
+			ingress.Labels = tt.labels
+			iw := NewIngressWrapper(ingress).WithNamespace(namespace).WithDerivationRules(rules).WithRecommendedLabels(true)
+			if got := iw.GetGroupDisplayName(); got != tt.want || iw.IsGroupFromNamespace() {
+				t.Errorf("IngressWrapper.GetGroupDisplayName() = %v, want %v not from namespace", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIngressWrapper_RecommendedLabels(t *testing.T) {
+	ingress := testutil.CreateIngressWithNamespace("grafana-ingress", "monitoring")
+	ingress.Labels = map[string]string{
+		annotations.AppNameLabel:      "Grafana",
+		annotations.AppPartOfLabel:    "Observability",
+		annotations.AppVersionLabel:   "10.4.1",
+		annotations.AppComponentLabel: "dashboard",
+	}
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		annotations map[string]string
+		wantName    string
+		wantGroup   string
+		wantVersion string
+	}{
+		{
+			name:      "Disabled",
+			wantName:  "grafana-ingress",
+			wantGroup: "monitoring",
+		},
+		{
+			name:        "Enabled",
+			enabled:     true,
+			wantName:    "Grafana",
+			wantGroup:   "Observability",
+			wantVersion: "10.4.1",
+		},
+		{
+			name:    "AnnotationsTakePrecedence",
+			enabled: true,
+			annotations: map[string]string{
+				annotations.ForecastleAppNameAnnotation: "Dashboards",
+				annotations.ForecastleGroupAnnotation:   "Ops",
+			},
+			wantName:    "Dashboards",
+			wantGroup:   "Ops",
+			wantVersion: "10.4.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ing := ingress.DeepCopy()
+			ing.Annotations = tt.annotations
+			iw := NewIngressWrapper(ing).WithRecommendedLabels(tt.enabled)
+			if got := iw.GetName(); got != tt.wantName {
+				t.Errorf("IngressWrapper.GetName() = %v, want %v", got, tt.wantName)
+			}
+			if got := iw.GetGroupDisplayName(); got != tt.wantGroup {
+				t.Errorf("IngressWrapper.GetGroupDisplayName() = %v, want %v", got, tt.wantGroup)
+			}
+			if got := iw.GetRecommendedLabel(annotations.AppVersionLabel); got != tt.wantVersion {
+				t.Errorf("IngressWrapper.GetRecommendedLabel() = %v, want %v", got, tt.wantVersion)
+			}
+			if got := iw.IsGroupFromNamespace(); got != !tt.enabled {
+				t.Errorf("IngressWrapper.IsGroupFromNamespace() = %v, want %v", got, !tt.enabled)
+			}
+		})
+	}
+}
+
 func TestIngressWrapper_GetNamespace(t *testing.T) {
 	type fields struct {
 		ingress *v1.Ingress
@@ -233,6 +359,45 @@ func TestIngressWrapper_GetURL(t *testing.T) {
 	}
 }
 
+func TestIngressWrapper_ResolveURL(t *testing.T) {
+	implementationSpecific := v1.PathTypeImplementationSpecific
+
+	rewrite := testutil.AddAnnotationToIngress(
+		testutil.CreateIngressWithHostAndSubPath("rewrite", "example.com", "/app(/|$)(.*)", "80"),
+		annotations.NginxRewriteTargetAnnotation, "/$2")
+	rewrite.Spec.Rules[0].HTTP.Paths[0].PathType = &implementationSpecific
+
+	wildcard := testutil.CreateIngressWithHost("wildcard", "*.example.com")
+	wildcardAndHost := testutil.CreateIngressWithHost("wildcard-and-host", "*.example.com")
+	wildcardAndHost.Spec.Rules = append(wildcardAndHost.Spec.Rules, v1.IngressRule{Host: "app.example.com"})
+
+	ipv6 := testutil.CreateIngressWithStatusIPHost("ipv6", "fd00::1")
+
+	tests := []struct {
+		name    string
+		ingress *v1.Ingress
+		want    string
+		wantErr bool
+	}{
+		{name: "RegexPath", ingress: rewrite, want: "http://example.com/app"},
+		{name: "WildcardHost", ingress: wildcard, wantErr: true},
+		{name: "WildcardHostIsSkipped", ingress: wildcardAndHost, want: "http://app.example.com"},
+		{name: "IPv6StatusAddress", ingress: ipv6, want: "http://[fd00::1]"},
+		{name: "NoHost", ingress: testutil.CreateIngress("no-host"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewIngressWrapper(tt.ingress).ResolveURL()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IngressWrapper.ResolveURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IngressWrapper.ResolveURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIngressWrapper_rulesExist(t *testing.T) {
 	type fields struct {
 		ingress *v1.Ingress
@@ -528,3 +693,53 @@ func TestIngressWrapper_GetGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestIngressWrapper_GetIngressClassName(t *testing.T) {
+	specClass := "internal"
+	emptyClass := ""
+	tests := []struct {
+		name             string
+		ingressClassName *string
+		annotationValue  string
+		want             string
+	}{
+		{
+			name: "IngressWithoutClass",
+			want: "",
+		},
+		{
+			name:             "IngressWithSpecClass",
+			ingressClassName: &specClass,
+			want:             "internal",
+		},
+		{
+			name:            "IngressWithLegacyAnnotation",
+			annotationValue: "public",
+			want:            "public",
+		},
+		{
+			name:             "IngressWithSpecClassAndLegacyAnnotation",
+			ingressClassName: &specClass,
+			annotationValue:  "public",
+			want:             "internal",
+		},
+		{
+			name:             "IngressWithEmptySpecClassAndLegacyAnnotation",
+			ingressClassName: &emptyClass,
+			annotationValue:  "public",
+			want:             "public",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingress := testutil.CreateIngress("someIngress")
+			ingress.Spec.IngressClassName = tt.ingressClassName
+			if tt.annotationValue != "" {
+				ingress = testutil.AddAnnotationToIngress(ingress, annotations.IngressClassAnnotation, tt.annotationValue)
+			}
+			if got := NewIngressWrapper(ingress).GetIngressClassName(); got != tt.want {
+				t.Errorf("IngressWrapper.GetIngressClassName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package wrappers
 
 import (
+	"slices"
+
 	"mvdan.cc/xurls/v2"
 
 	"github.com/stakater/Forecastle/v1/pkg/annotations"
 	ingressroutev1 "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // IngressRouteWrapper struct wraps a Traefik ingressroute object
@@ -50,3 +53,21 @@ func (irw *IngressRouteWrapper) GetURL() string {
 	}
 	return prefix + parsedUrl
 }
+
+// GetMiddlewares returns the Traefik middlewares referenced by the routes of the ingressroute. Middlewares
+// without a namespace are in the namespace of the ingressroute
+func (irw *IngressRouteWrapper) GetMiddlewares() []types.NamespacedName {
+	var middlewares []types.NamespacedName
+	for _, route := range irw.ingressroute.Spec.Routes {
+		for _, ref := range route.Middlewares {
+			middleware := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+			if middleware.Namespace == "" {
+				middleware.Namespace = irw.ingressroute.Namespace
+			}
+			if !slices.Contains(middlewares, middleware) {
+				middlewares = append(middlewares, middleware)
+			}
+		}
+	}
+	return middlewares
+}
@@ -0,0 +1,72 @@
+package wrappers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+	"go.yaml.in/yaml/v3"
+)
+
+// parseLinks parses the links annotation, either a YAML or JSON list of objects with a label, url and
+// optional icon, or a comma separated list of label:url pairs. Invalid links are skipped and returned as errors
+func parseLinks(value string) ([]forecastle.Link, []error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &document); err == nil && len(document.Content) == 1 &&
+		document.Content[0].Kind == yaml.SequenceNode {
+		var links []forecastle.Link
+		if err := document.Content[0].Decode(&links); err != nil {
+			return nil, []error{fmt.Errorf("links must be a list of objects with a label, url and icon: %w", err)}
+		}
+		return ValidLinks(links)
+	}
+
+	var links []forecastle.Link
+	var errs []error
+	for _, linkParam := range strings.Split(value, ",") {
+		label, url, ok := strings.Cut(linkParam, ":")
+		if !ok {
+			errs = append(errs, fmt.Errorf("link %q is not a label:url pair", linkParam))
+			continue
+		}
+		links = append(links, forecastle.Link{Label: strings.TrimSpace(label), URL: strings.TrimSpace(url)})
+	}
+	valid, validErrs := ValidLinks(links)
+	return valid, append(errs, validErrs...)
+}
+
+// ValidLinks returns the links that have a label and a URL with a scheme, with their URLs normalized.
+// Other links are skipped and returned as errors
+func ValidLinks(links []forecastle.Link) ([]forecastle.Link, []error) {
+	var valid []forecastle.Link
+	var errs []error
+	for _, link := range links {
+		if link.Label == "" {
+			errs = append(errs, errors.New("link to "+link.URL+" has no label"))
+			continue
+		}
+		url, err := ParseURL(link.URL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("link %q: %w", link.Label, err))
+			continue
+		}
+		link.URL = url
+		valid = append(valid, link)
+	}
+	return valid, errs
+}
+
+// getLinks parses the links annotation in annots, logging invalid links
+func getLinks(annots map[string]string) []forecastle.Link {
+	value := getAnnotationValue(annots, annotations.ForecastleLinksAnnotation)
+	if value == "" {
+		return nil
+	}
+	links, errs := parseLinks(value)
+	for _, err := range errs {
+		logger.Warn(err)
+	}
+	return links
+}
@@ -0,0 +1,66 @@
+package wrappers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/forecastle"
+)
+
+func Test_parseLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		want     []forecastle.Link
+		wantErrs int
+	}{
+		{
+			name:  "Pairs",
+			value: "Docs:https://docs.example.com, Runbook: https://wiki.example.com/runbook",
+			want: []forecastle.Link{
+				{Label: "Docs", URL: "https://docs.example.com"},
+				{Label: "Runbook", URL: "https://wiki.example.com/runbook"},
+			},
+		},
+		{
+			name:     "PairsWithInvalidEntries",
+			value:    "Docs:https://docs.example.com,Runbook,Grafana:grafana.example.com",
+			want:     []forecastle.Link{{Label: "Docs", URL: "https://docs.example.com"}},
+			wantErrs: 2,
+		},
+		{
+			name: "YAMLList",
+			value: "- label: Dashboard\n" +
+				"  url: https://grafana.example.com/d/app?from=now-1h,now\n" +
+				"  icon: https://grafana.example.com/icon.png\n",
+			want: []forecastle.Link{{
+				Label: "Dashboard",
+				URL:   "https://grafana.example.com/d/app?from=now-1h,now",
+				Icon:  "https://grafana.example.com/icon.png",
+			}},
+		},
+		{
+			name:     "JSONListWithMissingLabel",
+			value:    `[{"label": "Docs", "url": "https://docs.example.com"}, {"url": "https://wiki.example.com"}]`,
+			want:     []forecastle.Link{{Label: "Docs", URL: "https://docs.example.com"}},
+			wantErrs: 1,
+		},
+		{
+			name:     "ListOfStrings",
+			value:    `["https://docs.example.com"]`,
+			want:     nil,
+			wantErrs: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := parseLinks(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinks() = %v, want %v", got, tt.want)
+			}
+			if len(errs) != tt.wantErrs {
+				t.Errorf("parseLinks() errs = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
@@ -1,20 +1,135 @@
 package wrappers
 
 import (
+	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	"go.yaml.in/yaml/v3"
 )
 
-func makeMap(value string) map[string]string {
+// splitProperty splits a key:value pair of a properties annotation
+func splitProperty(propertyParam string) (key string, value string, ok bool) {
+	return strings.Cut(propertyParam, ":")
+}
+
+// makeMap parses the legacy key:value,key:value properties format. Pairs without a colon are skipped and
+// returned as errors
+func makeMap(value string) (map[string]string, []error) {
 	propertiesMap := make(map[string]string)
+	var errs []error
 
 	propertyParams := strings.Split(value, ",")
 	for _, propertyParam := range propertyParams {
-		splitted := strings.SplitN(propertyParam, ":", 2)
-		propertiesMap[splitted[0]] = splitted[1]
+		key, value, ok := splitProperty(propertyParam)
+		if !ok {
+			errs = append(errs, fmt.Errorf("property %q is not a key:value pair", propertyParam))
+			continue
+		}
+		propertiesMap[key] = value
+	}
+
+	return propertiesMap, errs
+}
+
+// parseTags parses a comma separated list of tags, dropping empty ones
+func parseTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseWeight parses the weight of an app. Returns 0 if no weight is set
+func parseWeight(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	weight, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("weight %q is not an integer", value)
+	}
+	return weight, nil
+}
+
+// parseStructuredProperties parses a YAML or JSON object of properties. Scalar values are kept verbatim,
+// so 1.0 stays 1.0
+func parseStructuredProperties(value string) (map[string]string, error) {
+	var nodes map[string]yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &nodes); err != nil {
+		return nil, fmt.Errorf("properties must be a YAML or JSON object: %w", err)
+	}
+
+	properties := make(map[string]string, len(nodes))
+	for key, node := range nodes {
+		if node.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("property %q must be a string, number or boolean", key)
+		}
+		if node.Tag != "!!null" {
+			properties[key] = node.Value
+		}
+	}
+	return properties, nil
+}
+
+// parseProperties parses the properties set by the forecastle annotations in annots. The legacy properties
+// annotation is overlaid by the structured one, which is overlaid by property.<key> annotations. Malformed
+// properties are skipped and returned as errors. Returns nil properties if none are set
+func parseProperties(annots map[string]string) (map[string]string, []error) {
+	var properties map[string]string
+	var errs []error
+	set := func(key string, value string) {
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		properties[key] = value
+	}
+
+	if value := getAnnotationValue(annots, annotations.ForecastlePropertiesAnnotation); value != "" {
+		legacy, legacyErrs := makeMap(value)
+		for key, value := range legacy {
+			set(key, value)
+		}
+		for _, err := range legacyErrs {
+			errs = append(errs, fmt.Errorf("%v: %w", annotations.ForecastlePropertiesAnnotation, err))
+		}
 	}
 
-	return propertiesMap
+	if value := getAnnotationValue(annots, annotations.ForecastleStructuredPropertiesAnnotation); value != "" {
+		structured, err := parseStructuredProperties(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", annotations.ForecastleStructuredPropertiesAnnotation, err))
+		}
+		for key, value := range structured {
+			set(key, value)
+		}
+	}
+
+	for annotation, value := range annots {
+		if key, ok := strings.CutPrefix(annotation, annotations.ForecastlePropertyAnnotationPrefix); ok {
+			if key == "" {
+				errs = append(errs, fmt.Errorf("%v: property key is empty", annotation))
+				continue
+			}
+			set(key, value)
+		}
+	}
+
+	return properties, errs
+}
+
+// getProperties parses the properties set by the forecastle annotations in annots, logging malformed ones
+func getProperties(annots map[string]string) map[string]string {
+	properties, errs := parseProperties(annots)
+	for _, err := range errs {
+		logger.Warn(err)
+	}
+	return properties
 }
 
 func getAnnotationValue(annotations map[string]string, key string) string {
@@ -32,15 +147,22 @@ func getAndValidateURLAnnotation(annotations map[string]string, key string) stri
 		return ""
 	}
 
-	parsedURL, err := url.Parse(urlValue)
+	parsedURL, err := ParseURL(urlValue)
 	if err != nil {
 		logger.Warn(err)
 		return ""
 	}
+	return parsedURL
+}
+
+// ParseURL parses an app URL as given in annotations and custom resources. URLs must be absolute
+func ParseURL(value string) (string, error) {
+	parsedURL, err := url.Parse(value)
+	if err != nil {
+		return "", err
+	}
 	if parsedURL.Scheme == "" {
-		logger.Warnf("URL %q is missing a scheme", urlValue)
-		return ""
+		return "", fmt.Errorf("URL %q is missing a scheme", value)
 	}
-
-	return parsedURL.String()
+	return parsedURL.String(), nil
 }
@@ -0,0 +1,109 @@
+package wrappers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+)
+
+func Test_makeMap(t *testing.T) {
+	got, errs := makeMap("Version:1.0,Owner,Docs:https://docs.example.com")
+	want := map[string]string{"Version": "1.0", "Docs": "https://docs.example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("makeMap() = %v, want %v", got, want)
+	}
+	if len(errs) != 1 || errs[0].Error() != `property "Owner" is not a key:value pair` {
+		t.Errorf("makeMap() errs = %v, want a single error for Owner", errs)
+	}
+}
+
+func Test_parseProperties(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+		wantErrs    int
+	}{
+		{
+			name:        "NoProperties",
+			annotations: map[string]string{annotations.ForecastleExposeAnnotation: "true"},
+			want:        nil,
+		},
+		{
+			name:        "Legacy",
+			annotations: map[string]string{annotations.ForecastlePropertiesAnnotation: "Version:1.0,Owner:team-a"},
+			want:        map[string]string{"Version": "1.0", "Owner": "team-a"},
+		},
+		{
+			name:        "LegacyWithMalformedPair",
+			annotations: map[string]string{annotations.ForecastlePropertiesAnnotation: "Version:1.0,,Owner"},
+			want:        map[string]string{"Version": "1.0"},
+			wantErrs:    2,
+		},
+		{
+			name: "StructuredYAML",
+			annotations: map[string]string{annotations.ForecastleStructuredPropertiesAnnotation: "" +
+				"Version: 1.0\n" +
+				"Replicas: 3\n" +
+				"Public: true\n" +
+				"Owners: \"team-a, team-b\"\n" +
+				"Unset: null\n"},
+			want: map[string]string{"Version": "1.0", "Replicas": "3", "Public": "true", "Owners": "team-a, team-b"},
+		},
+		{
+			name:        "StructuredJSON",
+			annotations: map[string]string{annotations.ForecastleStructuredPropertiesAnnotation: `{"Owners": "team-a, team-b", "Port": 8080}`},
+			want:        map[string]string{"Owners": "team-a, team-b", "Port": "8080"},
+		},
+		{
+			name:        "StructuredNotAnObject",
+			annotations: map[string]string{annotations.ForecastleStructuredPropertiesAnnotation: "- Version"},
+			want:        nil,
+			wantErrs:    1,
+		},
+		{
+			name:        "StructuredNestedValue",
+			annotations: map[string]string{annotations.ForecastleStructuredPropertiesAnnotation: `{"Owner": {"team": "a"}}`},
+			want:        nil,
+			wantErrs:    1,
+		},
+		{
+			name: "Precedence",
+			annotations: map[string]string{
+				annotations.ForecastlePropertiesAnnotation:               "Version:1.0,Owner:legacy,Tier:gold",
+				annotations.ForecastleStructuredPropertiesAnnotation:     "Version: 2.0\nOwner: structured",
+				annotations.ForecastlePropertyAnnotationPrefix + "Owner": "per-key",
+			},
+			want: map[string]string{"Version": "2.0", "Owner": "per-key", "Tier": "gold"},
+		},
+		{
+			name:        "PerKeyWithEmptyKey",
+			annotations: map[string]string{annotations.ForecastlePropertyAnnotationPrefix: "value"},
+			want:        nil,
+			wantErrs:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := parseProperties(tt.annotations)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProperties() = %v, want %v", got, tt.want)
+			}
+			if len(errs) != tt.wantErrs {
+				t.Errorf("parseProperties() errs = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func Test_parseTags(t *testing.T) {
+	got := parseTags(" monitoring, docs,,grafana ")
+	want := []string{"monitoring", "docs", "grafana"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTags() = %v, want %v", got, want)
+	}
+	if got := parseTags(""); got != nil {
+		t.Errorf("parseTags(\"\") = %v, want nil", got)
+	}
+}
@@ -0,0 +1,97 @@
+package wrappers
+
+import (
+	"slices"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// namespaceDefaultableAnnotations lists the forecastle annotations that can be set on a namespace
+// to act as defaults for every app discovered in it
+var namespaceDefaultableAnnotations = []string{
+	annotations.ForecastleGroupAnnotation,
+	annotations.ForecastleIconAnnotation,
+	annotations.ForecastleInstanceAnnotation,
+	annotations.ForecastleNetworkRestrictedAnnotation,
+	annotations.ForecastleRequiresAuthAnnotation,
+	annotations.ForecastleAllowedGroupsAnnotation,
+	annotations.ForecastlePropertiesAnnotation,
+	annotations.ForecastleStructuredPropertiesAnnotation,
+}
+
+// NamespaceWrapper struct wraps a kubernetes namespace object whose forecastle annotations and labels
+// act as defaults for the apps discovered in it. A nil NamespaceWrapper provides no defaults
+type NamespaceWrapper struct {
+	namespace *corev1.Namespace
+}
+
+// NewNamespaceWrapper func creates an instance of NamespaceWrapper
+func NewNamespaceWrapper(namespace *corev1.Namespace) *NamespaceWrapper {
+	if namespace == nil {
+		return nil
+	}
+	return &NamespaceWrapper{
+		namespace: namespace,
+	}
+}
+
+// GetAnnotationValue extracts a default value from the namespace annotations, falling back to its labels
+func (nw *NamespaceWrapper) GetAnnotationValue(annotationKey string) string {
+	if nw == nil || !slices.Contains(namespaceDefaultableAnnotations, annotationKey) {
+		return ""
+	}
+	if value := getAnnotationValue(nw.namespace.Annotations, annotationKey); value != "" {
+		return value
+	}
+	return getAnnotationValue(nw.namespace.Labels, annotationKey)
+}
+
+// GetDisplayName func returns the human friendly name of the namespace, preferring the OpenShift
+// display name annotation over the namespace name
+func (nw *NamespaceWrapper) GetDisplayName() string {
+	if nw == nil {
+		return ""
+	}
+	if displayName := getAnnotationValue(nw.namespace.Annotations, annotations.OpenShiftDisplayNameAnnotation); displayName != "" {
+		return displayName
+	}
+	return nw.namespace.Name
+}
+
+// GetMetadata returns the labels and annotations of the namespace
+func (nw *NamespaceWrapper) GetMetadata() (labels map[string]string, annotations map[string]string) {
+	if nw == nil {
+		return nil, nil
+	}
+	return nw.namespace.Labels, nw.namespace.Annotations
+}
+
+// GetAllowedGroups parses the user groups allowed to see the apps of the namespace by default
+func (nw *NamespaceWrapper) GetAllowedGroups() []string {
+	return parseTags(nw.GetAnnotationValue(annotations.ForecastleAllowedGroupsAnnotation))
+}
+
+// GetProperties parses the default properties of the namespace
+func (nw *NamespaceWrapper) GetProperties() map[string]string {
+	if nw == nil {
+		return nil
+	}
+	return getProperties(nw.namespace.Annotations)
+}
+
+// mergeProperties overlays properties on top of defaults. Returns nil if both are empty
+func mergeProperties(defaults map[string]string, properties map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return properties
+	}
+
+	merged := make(map[string]string, len(defaults)+len(properties))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range properties {
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,156 @@
+package wrappers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+	"github.com/stakater/Forecastle/v1/pkg/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceWrapper_GetAnnotationValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     *corev1.Namespace
+		annotationKey string
+		want          string
+	}{
+		{
+			name:          "NilNamespace",
+			namespace:     nil,
+			annotationKey: annotations.ForecastleGroupAnnotation,
+			want:          "",
+		},
+		{
+			name: "FromAnnotation",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "team-a",
+				Annotations: map[string]string{annotations.ForecastleGroupAnnotation: "Team A"},
+			}},
+			annotationKey: annotations.ForecastleGroupAnnotation,
+			want:          "Team A",
+		},
+		{
+			name: "FromLabel",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "team-a",
+				Labels: map[string]string{annotations.ForecastleInstanceAnnotation: "internal"},
+			}},
+			annotationKey: annotations.ForecastleInstanceAnnotation,
+			want:          "internal",
+		},
+		{
+			name: "AnnotationTakesPrecedenceOverLabel",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "team-a",
+				Annotations: map[string]string{annotations.ForecastleInstanceAnnotation: "public"},
+				Labels:      map[string]string{annotations.ForecastleInstanceAnnotation: "internal"},
+			}},
+			annotationKey: annotations.ForecastleInstanceAnnotation,
+			want:          "public",
+		},
+		{
+			name: "NonDefaultableAnnotationIgnored",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "team-a",
+				Annotations: map[string]string{annotations.ForecastleExposeAnnotation: "true"},
+			}},
+			annotationKey: annotations.ForecastleExposeAnnotation,
+			want:          "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewNamespaceWrapper(tt.namespace).GetAnnotationValue(tt.annotationKey); got != tt.want {
+				t.Errorf("NamespaceWrapper.GetAnnotationValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamespaceWrapper_GetDisplayName(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace *corev1.Namespace
+		want      string
+	}{
+		{
+			name:      "NilNamespace",
+			namespace: nil,
+			want:      "",
+		},
+		{
+			name:      "WithoutDisplayName",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+			want:      "team-a",
+		},
+		{
+			name: "WithOpenShiftDisplayName",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "team-a",
+				Annotations: map[string]string{annotations.OpenShiftDisplayNameAnnotation: "Team A"},
+			}},
+			want: "Team A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewNamespaceWrapper(tt.namespace).GetDisplayName(); got != tt.want {
+				t.Errorf("NamespaceWrapper.GetDisplayName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIngressWrapper_WithNamespace(t *testing.T) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "team-a",
+		Annotations: map[string]string{
+			annotations.OpenShiftDisplayNameAnnotation:    "Team A",
+			annotations.ForecastleIconAnnotation:          "https://example.com/team.png",
+			annotations.ForecastlePropertiesAnnotation:    "Owner:team-a,Tier:gold",
+			annotations.ForecastleAllowedGroupsAnnotation: "team-a, platform",
+		},
+	}}
+
+	t.Run("NamespaceDefaultsApply", func(t *testing.T) {
+		iw := NewIngressWrapper(testutil.CreateIngressWithNamespace("someIngress", "team-a")).WithNamespace(namespace)
+
+		if got := iw.GetGroup(); got != "team a" {
+			t.Errorf("IngressWrapper.GetGroup() = %v, want %v", got, "team a")
+		}
+		if got := iw.GetAnnotationValue(annotations.ForecastleIconAnnotation); got != "https://example.com/team.png" {
+			t.Errorf("IngressWrapper.GetAnnotationValue() = %v, want %v", got, "https://example.com/team.png")
+		}
+		want := map[string]string{"Owner": "team-a", "Tier": "gold"}
+		if got := iw.GetProperties(); !reflect.DeepEqual(got, want) {
+			t.Errorf("IngressWrapper.GetProperties() = %v, want %v", got, want)
+		}
+		if got := iw.GetAllowedGroups(); !reflect.DeepEqual(got, []string{"team-a", "platform"}) {
+			t.Errorf("IngressWrapper.GetAllowedGroups() = %v, want %v", got, []string{"team-a", "platform"})
+		}
+	})
+
+	t.Run("IngressOverridesNamespaceDefaults", func(t *testing.T) {
+		ingress := testutil.AddAnnotationToIngress(
+			testutil.AddAnnotationToIngress(
+				testutil.AddAnnotationToIngress(testutil.CreateIngressWithNamespace("someIngress", "team-a"),
+					annotations.ForecastleGroupAnnotation, "Tools"),
+				annotations.ForecastleIconAnnotation, "https://example.com/app.png"),
+			annotations.ForecastlePropertiesAnnotation, "Tier:silver,Version:1.0")
+		iw := NewIngressWrapper(ingress).WithNamespace(namespace)
+
+		if got := iw.GetGroup(); got != "tools" {
+			t.Errorf("IngressWrapper.GetGroup() = %v, want %v", got, "tools")
+		}
+		if got := iw.GetAnnotationValue(annotations.ForecastleIconAnnotation); got != "https://example.com/app.png" {
+			t.Errorf("IngressWrapper.GetAnnotationValue() = %v, want %v", got, "https://example.com/app.png")
+		}
+		want := map[string]string{"Owner": "team-a", "Tier": "silver", "Version": "1.0"}
+		if got := iw.GetProperties(); !reflect.DeepEqual(got, want) {
+			t.Errorf("IngressWrapper.GetProperties() = %v, want %v", got, want)
+		}
+	})
+}
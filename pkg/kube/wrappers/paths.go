@@ -0,0 +1,77 @@
+package wrappers
+
+import (
+	"net"
+	"strings"
+	"unicode"
+
+	v1 "k8s.io/api/networking/v1"
+)
+
+// regexMetaCharacters are the characters that end the literal prefix of a regular expression path
+const regexMetaCharacters = `.*+?()[]{}|^$\`
+
+// isWildcardHost returns true if host is a wildcard host such as *.example.com, which can't be linked to
+func isWildcardHost(host string) bool {
+	return strings.HasPrefix(host, "*")
+}
+
+// formatHost returns host as it appears in a URL, enclosing IPv6 addresses in brackets
+func formatHost(host string) string {
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// normalizeIngressPath returns the literal path prefix an ingress path matches, which can be appended to a URL.
+// Regular expression paths are cut at their first meta character, and glob paths at their trailing *
+func normalizeIngressPath(path string, pathType *v1.PathType, regex bool) string {
+	if path == "" {
+		return ""
+	}
+
+	exact := pathType != nil && *pathType == v1.PathTypeExact
+	implementationSpecific := pathType == nil || *pathType == v1.PathTypeImplementationSpecific
+	switch {
+	case exact:
+	case regex:
+		path = regexLiteralPrefix(path)
+	case implementationSpecific:
+		path = strings.TrimSuffix(path, "*")
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// regexLiteralPrefix returns the literal prefix of a regular expression path. Paths cut inside a segment,
+// like /api/v[0-9]+, are cut back to their last complete segment, unless the meta character opens a group
+// like in /app(/|$)(.*) or matches anything like in /app.*
+func regexLiteralPrefix(expr string) string {
+	expr = strings.TrimPrefix(expr, "^")
+
+	var prefix strings.Builder
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if c == '\\' && i+1 < len(expr) && !unicode.IsLetter(rune(expr[i+1])) && !unicode.IsDigit(rune(expr[i+1])) {
+			// Escaped punctuation is literal
+			i++
+			prefix.WriteByte(expr[i])
+			continue
+		}
+		if !strings.ContainsRune(regexMetaCharacters, rune(c)) {
+			prefix.WriteByte(c)
+			continue
+		}
+
+		literal := prefix.String()
+		if c == '(' || strings.HasPrefix(expr[i:], ".*") {
+			return literal
+		}
+		return literal[:strings.LastIndex(literal, "/")+1]
+	}
+	return prefix.String()
+}
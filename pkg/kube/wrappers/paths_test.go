@@ -0,0 +1,56 @@
+package wrappers
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/networking/v1"
+)
+
+func Test_normalizeIngressPath(t *testing.T) {
+	exact := v1.PathTypeExact
+	prefix := v1.PathTypePrefix
+	implementationSpecific := v1.PathTypeImplementationSpecific
+
+	tests := []struct {
+		name     string
+		path     string
+		pathType *v1.PathType
+		regex    bool
+		want     string
+	}{
+		{name: "Empty", path: "", pathType: &prefix, want: ""},
+		{name: "Prefix", path: "/app", pathType: &prefix, want: "/app"},
+		{name: "Relative", path: "app", pathType: &prefix, want: "/app"},
+		{name: "Exact", path: "/app.html", pathType: &exact, regex: true, want: "/app.html"},
+		{name: "RewriteGroup", path: "/app(/|$)(.*)", pathType: &implementationSpecific, regex: true, want: "/app"},
+		{name: "TrailingGroup", path: "/app/(.*)", pathType: &implementationSpecific, regex: true, want: "/app/"},
+		{name: "AnchoredAnything", path: "^/app.*", regex: true, want: "/app"},
+		{name: "PartialSegment", path: "/api/v[0-9]+/users", pathType: &prefix, regex: true, want: "/api/"},
+		{name: "EscapedDot", path: `/docs/v1\.2/(.*)`, pathType: &implementationSpecific, regex: true, want: "/docs/v1.2/"},
+		{name: "CharacterClassEscape", path: `/app\d+`, pathType: &implementationSpecific, regex: true, want: "/"},
+		{name: "RegexOnly", path: "(.*)", pathType: &implementationSpecific, regex: true, want: "/"},
+		{name: "Glob", path: "/app/*", pathType: &implementationSpecific, want: "/app/"},
+		{name: "GlobWithoutPathType", path: "/*", want: "/"},
+		{name: "NotRegex", path: "/app.v1", pathType: &implementationSpecific, want: "/app.v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeIngressPath(tt.path, tt.pathType, tt.regex); got != tt.want {
+				t.Errorf("normalizeIngressPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formatHost(t *testing.T) {
+	tests := map[string]string{
+		"example.com": "example.com",
+		"10.0.0.1":    "10.0.0.1",
+		"fd00::1":     "[fd00::1]",
+	}
+	for host, want := range tests {
+		if got := formatHost(host); got != want {
+			t.Errorf("formatHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package wrappers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+)
+
+// knownAnnotations are the forecastle annotations read from Ingresses, HTTPRoutes and their namespaces
+var knownAnnotations = map[string]bool{
+	annotations.ForecastleIconAnnotation:                 true,
+	annotations.ForecastleExposeAnnotation:               true,
+	annotations.ForecastleAppNameAnnotation:              true,
+	annotations.ForecastleGroupAnnotation:                true,
+	annotations.ForecastleInstanceAnnotation:             true,
+	annotations.ForecastleNetworkRestrictedAnnotation:    true,
+	annotations.ForecastleRequiresAuthAnnotation:         true,
+	annotations.ForecastleAllowedGroupsAnnotation:        true,
+	annotations.ForecastleURLAnnotation:                  true,
+	annotations.ForecastlePropertiesAnnotation:           true,
+	annotations.ForecastleStructuredPropertiesAnnotation: true,
+	annotations.ForecastleAppIDAnnotation:                true,
+	annotations.ForecastleDescriptionAnnotation:          true,
+	annotations.ForecastleTagsAnnotation:                 true,
+	annotations.ForecastleLinksAnnotation:                true,
+}
+
+// ValidateAnnotations checks the forecastle annotations in annots the way the wrappers parse them. It returns
+// errors for annotations forecastle can't use, and warnings for values that are ignored or likely mistakes
+func ValidateAnnotations(annots map[string]string) (errs []string, warnings []string) {
+	keys := make([]string, 0, len(annots))
+	for key := range annotations.ForecastleAnnotations(annots) {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := annots[key]
+		switch key {
+		case annotations.ForecastleURLAnnotation:
+			if _, err := ParseURL(value); err != nil {
+				errs = append(errs, fmt.Sprintf("%v: %v", key, err))
+			}
+		case annotations.ForecastleExposeAnnotation:
+			if value != "true" && value != "false" {
+				warnings = append(warnings, fmt.Sprintf("%v: %q is not \"true\", the app is not exposed", key, value))
+			}
+		case annotations.ForecastleNetworkRestrictedAnnotation:
+			if _, err := strconv.ParseBool(value); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%v: %q is not a boolean, the app is not network restricted", key, value))
+			}
+		case annotations.ForecastleRequiresAuthAnnotation:
+			if _, err := strconv.ParseBool(value); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%v: %q is not a boolean and is ignored", key, value))
+			}
+		case annotations.ForecastleLinksAnnotation:
+			_, linkErrs := parseLinks(value)
+			for _, err := range linkErrs {
+				errs = append(errs, fmt.Sprintf("%v: %v", key, err))
+			}
+		case annotations.ForecastleWeightAnnotation:
+			if _, err := parseWeight(value); err != nil {
+				errs = append(errs, fmt.Sprintf("%v: %v", key, err))
+			}
+		case annotations.ForecastleIconAnnotation:
+			if _, err := ParseURL(value); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%v: %v", key, err))
+			}
+		default:
+			if !knownAnnotations[key] && !strings.HasPrefix(key, annotations.ForecastlePropertyAnnotationPrefix) {
+				warnings = append(warnings, fmt.Sprintf("%v: unknown forecastle annotation", key))
+			}
+		}
+	}
+
+	_, propertyErrs := parseProperties(annots)
+	for _, err := range propertyErrs {
+		errs = append(errs, err.Error())
+	}
+	return errs, warnings
+}
@@ -0,0 +1,78 @@
+package wrappers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stakater/Forecastle/v1/pkg/annotations"
+)
+
+func TestValidateAnnotations(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantErrs     []string
+		wantWarnings []string
+	}{
+		{
+			name: "Valid",
+			annotations: map[string]string{
+				annotations.ForecastleExposeAnnotation:     "true",
+				annotations.ForecastleURLAnnotation:        "https://app.example.com",
+				annotations.ForecastlePropertiesAnnotation: "Version:1.0,Docs:https://docs.example.com",
+				"kubernetes.io/ingress.class":              "nginx",
+			},
+		},
+		{
+			name:        "URLWithoutScheme",
+			annotations: map[string]string{annotations.ForecastleURLAnnotation: "app.example.com"},
+			wantErrs:    []string{`forecastle.stakater.com/url: URL "app.example.com" is missing a scheme`},
+		},
+		{
+			name:        "PropertyWithoutColon",
+			annotations: map[string]string{annotations.ForecastlePropertiesAnnotation: "Version:1.0,Owner"},
+			wantErrs:    []string{`forecastle.stakater.com/properties: property "Owner" is not a key:value pair`},
+		},
+		{
+			name: "StructuredAndPerKeyProperties",
+			annotations: map[string]string{
+				annotations.ForecastleStructuredPropertiesAnnotation:    `{"Owners": "team-a, team-b"}`,
+				annotations.ForecastlePropertyAnnotationPrefix + "Tier": "gold",
+			},
+		},
+		{
+			name:        "StructuredPropertiesWithNestedValue",
+			annotations: map[string]string{annotations.ForecastleStructuredPropertiesAnnotation: `{"Owner": {"team": "a"}}`},
+			wantErrs:    []string{`forecastle.stakater.com/properties-yaml: property "Owner" must be a string, number or boolean`},
+		},
+		{
+			name:        "WeightNotAnInteger",
+			annotations: map[string]string{annotations.ForecastleWeightAnnotation: "first"},
+			wantErrs:    []string{`forecastle.stakater.com/weight: weight "first" is not an integer`},
+		},
+		{
+			name: "Warnings",
+			annotations: map[string]string{
+				annotations.ForecastleExposeAnnotation:            "yes",
+				annotations.ForecastleNetworkRestrictedAnnotation: "maybe",
+				"forecastle.stakater.com/colour":                  "blue",
+			},
+			wantWarnings: []string{
+				`forecastle.stakater.com/colour: unknown forecastle annotation`,
+				`forecastle.stakater.com/expose: "yes" is not "true", the app is not exposed`,
+				`forecastle.stakater.com/network-restricted: "maybe" is not a boolean, the app is not network restricted`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, warnings := ValidateAnnotations(tt.annotations)
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("ValidateAnnotations() errs = %q, want %q", errs, tt.wantErrs)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("ValidateAnnotations() warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
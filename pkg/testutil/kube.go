@@ -4,7 +4,7 @@ import (
 	"strconv"
 
 	routev1 "github.com/openshift/api/route/v1"
-	v1alpha1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1alpha1"
+	v1beta1 "github.com/stakater/Forecastle/v1/pkg/apis/forecastle/v1beta1"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -141,12 +141,12 @@ func CreateIngressWithStatusIPHost(name string, host string) *v1.Ingress {
 	return ingress
 }
 
-func CreateForecastleApp(name string, url string, group string, icon string) *v1alpha1.ForecastleApp {
-	return &v1alpha1.ForecastleApp{
+func CreateForecastleApp(name string, url string, group string, icon string) *v1beta1.ForecastleApp {
+	return &v1beta1.ForecastleApp{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
-		Spec: v1alpha1.ForecastleAppSpec{
+		Spec: v1beta1.ForecastleAppSpec{
 			Name:  name,
 			URL:   url,
 			Icon:  icon,
@@ -155,11 +155,11 @@ func CreateForecastleApp(name string, url string, group string, icon string) *v1
 	}
 }
 
-func CreateForecastleAppWithURLFromRoute(name string, group string, icon string, routeName string) *v1alpha1.ForecastleApp {
+func CreateForecastleAppWithURLFromRoute(name string, group string, icon string, routeName string) *v1beta1.ForecastleApp {
 	forecastleApp := CreateForecastleApp(name, "", group, icon)
-	forecastleApp.Spec.URLFrom = &v1alpha1.URLSource{
-		RouteRef: &v1alpha1.RouteURLSource{
-			LocalObjectReference: v1alpha1.LocalObjectReference{
+	forecastleApp.Spec.URLFrom = &v1beta1.URLSource{
+		RouteRef: &v1beta1.RouteURLSource{
+			ObjectReference: v1beta1.ObjectReference{
 				Name: routeName,
 			},
 		},
@@ -168,11 +168,11 @@ func CreateForecastleAppWithURLFromRoute(name string, group string, icon string,
 	return forecastleApp
 }
 
-func CreateForecastleAppWithURLFromIngress(name string, group string, icon string, ingressName string) *v1alpha1.ForecastleApp {
+func CreateForecastleAppWithURLFromIngress(name string, group string, icon string, ingressName string) *v1beta1.ForecastleApp {
 	forecastleApp := CreateForecastleApp(name, "", group, icon)
-	forecastleApp.Spec.URLFrom = &v1alpha1.URLSource{
-		IngressRef: &v1alpha1.IngressURLSource{
-			LocalObjectReference: v1alpha1.LocalObjectReference{
+	forecastleApp.Spec.URLFrom = &v1beta1.URLSource{
+		IngressRef: &v1beta1.IngressURLSource{
+			ObjectReference: v1beta1.ObjectReference{
 				Name: ingressName,
 			},
 		},